@@ -0,0 +1,139 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"text/template"
+)
+
+// MessageCodeCatalogue is a machine-readable list of message codes, e.g. for documentation sites or SIEM rules.
+type MessageCodeCatalogue struct {
+	// Codes contains the message codes sorted by code.
+	Codes []MessageCodeInfo `json:"codes" yaml:"codes"`
+}
+
+// NewMessageCodeCatalogue creates a catalogue from the message codes returned by GetMessageCodes and similar
+// functions.
+func NewMessageCodeCatalogue(codes map[string]string) MessageCodeCatalogue {
+	catalogue := MessageCodeCatalogue{
+		Codes: make([]MessageCodeInfo, 0, len(codes)),
+	}
+	for code, description := range codes {
+		catalogue.Codes = append(catalogue.Codes, MessageCodeInfo{
+			Code:        code,
+			Description: description,
+		})
+	}
+	return catalogue.sorted()
+}
+
+// ParseMessageCodeCatalogueJSON reads a catalogue previously written by GenerateMessageCodeCatalogueJSON.
+func ParseMessageCodeCatalogueJSON(data []byte) (MessageCodeCatalogue, error) {
+	catalogue := MessageCodeCatalogue{}
+	if err := json.Unmarshal(data, &catalogue); err != nil {
+		return MessageCodeCatalogue{}, fmt.Errorf("failed to decode message code catalogue (%w)", err)
+	}
+	return catalogue.sorted(), nil
+}
+
+// sorted returns a copy of the catalogue with the codes sorted by code.
+func (c MessageCodeCatalogue) sorted() MessageCodeCatalogue {
+	result := MessageCodeCatalogue{Codes: append([]MessageCodeInfo{}, c.Codes...)}
+	sort.Slice(result.Codes, func(i, j int) bool {
+		return result.Codes[i].Code < result.Codes[j].Code
+	})
+	return result
+}
+
+// byCode returns the codes of the catalogue keyed by code.
+func (c MessageCodeCatalogue) byCode() map[string]MessageCodeInfo {
+	codes := make(map[string]MessageCodeInfo, len(c.Codes))
+	for _, info := range c.Codes {
+		codes[info.Code] = info
+	}
+	return codes
+}
+
+// GenerateMessageCodesJSON renders the message codes as an indented JSON catalogue.
+func GenerateMessageCodesJSON(codes map[string]string) (string, error) {
+	return GenerateMessageCodeCatalogueJSON(NewMessageCodeCatalogue(codes))
+}
+
+// GenerateMessageCodeCatalogueJSON renders a catalogue as indented JSON.
+func GenerateMessageCodeCatalogueJSON(catalogue MessageCodeCatalogue) (string, error) {
+	data, err := json.MarshalIndent(catalogue, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode message code catalogue (%w)", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// GenerateMessageCodesDocumentWithTemplate renders a document from the catalogue using a custom text/template. The
+// template is executed with the catalogue, so the codes can be listed using {{ range .Codes }}.
+func GenerateMessageCodesDocumentWithTemplate(catalogue MessageCodeCatalogue, templateText string) (string, error) {
+	tpl, err := template.New("CODES.md.tpl").Parse(templateText)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse codes template (%w)", err)
+	}
+	wr := &bytes.Buffer{}
+	if err := tpl.Execute(wr, catalogue); err != nil {
+		return "", fmt.Errorf("failed to render codes template (%w)", err)
+	}
+	return wr.String(), nil
+}
+
+// logPackagePath is the import path of this package, used to qualify the calls in generated registry files.
+const logPackagePath = "github.com/containerssh/log"
+
+// GenerateMessageCodesRegistry renders a Go source file that registers all codes of the catalogue in the runtime
+// registry on startup. The file belongs to the package with the specified name and import path. If the import path is
+// the path of this log package, the functions are called without the package qualifier. All non-empty fields of the
+// codes are included, so the registry contains the same metadata as the catalogue.
+func GenerateMessageCodesRegistry(
+	catalogue MessageCodeCatalogue,
+	packageName string,
+	importPath string,
+) (string, error) {
+	qualifier := "log."
+	buf := &bytes.Buffer{}
+	buf.WriteString("// Code generated by containerssh-generate-codes. DO NOT EDIT.\n\n")
+	_, _ = fmt.Fprintf(buf, "package %s\n\n", packageName)
+	if importPath == logPackagePath {
+		qualifier = ""
+	} else {
+		_, _ = fmt.Fprintf(buf, "import %s\n\n", strconv.Quote(logPackagePath))
+	}
+	buf.WriteString("func init() {\n")
+	for _, info := range catalogue.sorted().Codes {
+		_, _ = fmt.Fprintf(buf, "%sRegisterMessageCode(%sMessageCodeInfo{\n", qualifier, qualifier)
+		for _, field := range []struct {
+			name  string
+			value string
+		}{
+			{"Code", info.Code},
+			{"Description", info.Description},
+			{"Level", string(info.Level)},
+			{"UserMessage", info.UserMessage},
+			{"DocumentationURL", info.DocumentationURL},
+			{"Owner", info.Owner},
+			{"Severity", info.Severity},
+			{"Resolution", info.Resolution},
+			{"Since", info.Since},
+		} {
+			if field.value != "" || field.name == "Code" || field.name == "Description" {
+				_, _ = fmt.Fprintf(buf, "%s: %s,\n", field.name, strconv.Quote(field.value))
+			}
+		}
+		buf.WriteString("})\n")
+	}
+	buf.WriteString("}\n")
+	data, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("bug: failed to format generated registry (%w)", err)
+	}
+	return string(data), nil
+}
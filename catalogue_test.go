@@ -0,0 +1,74 @@
+package log_test
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+var testCatalogueCodes = map[string]string{
+	"B_CODE": "The second code.",
+	"A_CODE": "The first \"quoted\" code.",
+}
+
+func TestMessageCodeCatalogueJSON(t *testing.T) {
+	data, err := log.GenerateMessageCodesJSON(testCatalogueCodes)
+	assert.NoError(t, err)
+	catalogue := log.MessageCodeCatalogue{}
+	assert.NoError(t, json.Unmarshal([]byte(data), &catalogue))
+	assert.Equal(t, []log.MessageCodeInfo{
+		{Code: "A_CODE", Description: "The first \"quoted\" code."},
+		{Code: "B_CODE", Description: "The second code."},
+	}, catalogue.Codes)
+}
+
+func TestMessageCodeCatalogueGoRegistry(t *testing.T) {
+	catalogue := log.NewMessageCodeCatalogue(testCatalogueCodes)
+	catalogue.Codes[0].Level = log.LevelWarningString
+	catalogue.Codes[0].UserMessage = "Something went wrong."
+	catalogue.Codes[0].Resolution = "Try again."
+	data, err := log.GenerateMessageCodesRegistry(catalogue, "example", "example.com/example")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(data, "// Code generated by containerssh-generate-codes. DO NOT EDIT.\n"))
+	f, err := parser.ParseFile(token.NewFileSet(), "registry.go", data, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "example", f.Name.Name)
+	assert.Contains(t, data, `"github.com/containerssh/log"`)
+	assert.Contains(t, data, `log.RegisterMessageCode(log.MessageCodeInfo{`)
+	assert.Contains(t, data, `Description: "The first \"quoted\" code.",`)
+	assert.Contains(t, data, `Level:       "warning",`)
+	assert.Contains(t, data, `UserMessage: "Something went wrong.",`)
+	assert.Contains(t, data, `Resolution:  "Try again.",`)
+	assert.NotContains(t, data, "Since:")
+}
+
+func TestMessageCodeCatalogueGoRegistryInLogPackage(t *testing.T) {
+	data, err := log.GenerateMessageCodesRegistry(
+		log.NewMessageCodeCatalogue(testCatalogueCodes),
+		"log",
+		"github.com/containerssh/log",
+	)
+	assert.NoError(t, err)
+	assert.NotContains(t, data, "import")
+	assert.Contains(t, data, "\tRegisterMessageCode(MessageCodeInfo{")
+
+	data, err = log.GenerateMessageCodesRegistry(log.NewMessageCodeCatalogue(testCatalogueCodes), "log", "example.com/log")
+	assert.NoError(t, err)
+	assert.Contains(t, data, `log.RegisterMessageCode(log.MessageCodeInfo{`)
+}
+
+func TestGeneratedRegistry(t *testing.T) {
+	info, ok := log.GetMessageCodeInfo(log.ELogWriteFailed)
+	assert.True(t, ok)
+	assert.Equal(t, log.ELogWriteFailed, info.Code)
+	assert.Contains(t, info.Description, "cannot write to the specified log file")
+
+	_, ok = log.GetMessageCodeInfo("NONEXISTENT_CODE")
+	assert.False(t, ok)
+}
@@ -0,0 +1,290 @@
+package log
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// This file contains a minimal CBOR (RFC 8949) encoder and decoder covering the types used in log messages, so the
+// package does not need an external dependency.
+
+const (
+	cborMajorUint   byte = 0
+	cborMajorNegInt byte = 1
+	cborMajorBytes  byte = 2
+	cborMajorString byte = 3
+	cborMajorArray  byte = 4
+	cborMajorMap    byte = 5
+	cborMajorTag    byte = 6
+	cborMajorSimple byte = 7
+
+	// cborTagDateTime marks an RFC 3339 date/time string.
+	cborTagDateTime = 0
+	// cborTagEpoch marks a numeric timestamp in seconds since the Unix epoch.
+	cborTagEpoch = 1
+)
+
+type cborEncoder struct {
+	buf []byte
+}
+
+func (e *cborEncoder) bytes() []byte {
+	return e.buf
+}
+
+// writeHead writes the initial byte of a data item with the argument encoded in the shortest form.
+func (e *cborEncoder) writeHead(major byte, v uint64) {
+	switch {
+	case v < 24:
+		e.buf = append(e.buf, major<<5|byte(v))
+	case v <= math.MaxUint8:
+		e.buf = append(e.buf, major<<5|24, byte(v))
+	case v <= math.MaxUint16:
+		e.buf = append(e.buf, major<<5|25)
+		e.buf = appendUint16(e.buf, uint16(v))
+	case v <= math.MaxUint32:
+		e.buf = append(e.buf, major<<5|26)
+		e.buf = appendUint32(e.buf, uint32(v))
+	default:
+		e.buf = append(e.buf, major<<5|27)
+		e.buf = appendUint64(e.buf, v)
+	}
+}
+
+func (e *cborEncoder) writeNil() {
+	e.buf = append(e.buf, 0xf6)
+}
+
+func (e *cborEncoder) writeBool(v bool) {
+	if v {
+		e.buf = append(e.buf, 0xf5)
+	} else {
+		e.buf = append(e.buf, 0xf4)
+	}
+}
+
+func (e *cborEncoder) writeInt(v int64) {
+	if v >= 0 {
+		e.writeHead(cborMajorUint, uint64(v))
+	} else {
+		e.writeHead(cborMajorNegInt, uint64(-1-v))
+	}
+}
+
+func (e *cborEncoder) writeUint(v uint64) {
+	e.writeHead(cborMajorUint, v)
+}
+
+func (e *cborEncoder) writeFloat(v float64) {
+	e.buf = append(e.buf, 0xfb)
+	e.buf = appendUint64(e.buf, math.Float64bits(v))
+}
+
+func (e *cborEncoder) writeString(v string) {
+	e.writeHead(cborMajorString, uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *cborEncoder) writeBinary(v []byte) {
+	e.writeHead(cborMajorBytes, uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *cborEncoder) writeArrayHeader(l int) {
+	e.writeHead(cborMajorArray, uint64(l))
+}
+
+func (e *cborEncoder) writeMapHeader(l int) {
+	e.writeHead(cborMajorMap, uint64(l))
+}
+
+// writeValue writes an arbitrary label value. Types without a CBOR equivalent are written as strings.
+func (e *cborEncoder) writeValue(value interface{}) {
+	writeEncodedValue(e, value)
+}
+
+// writeTimestamp writes a timestamp as a tagged RFC 3339 string to preserve nanosecond precision and the time zone.
+func (e *cborEncoder) writeTimestamp(t time.Time) {
+	e.writeHead(cborMajorTag, cborTagDateTime)
+	e.writeString(t.Format(time.RFC3339Nano))
+}
+
+// cborDecoder reads CBOR values from a stream. Maps are decoded into map[string]interface{}, arrays into
+// []interface{}, integers into int64 or uint64 and date/time tags into time.Time. Indefinite length items are not
+// supported.
+type cborDecoder struct {
+	r *bufio.Reader
+}
+
+func newCBORDecoder(r io.Reader) *cborDecoder {
+	return &cborDecoder{r: bufio.NewReader(r)}
+}
+
+func (d *cborDecoder) decode() (interface{}, error) {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	major := b >> 5
+	if major == cborMajorSimple {
+		return d.decodeSimple(b & 0x1f)
+	}
+	arg, err := d.readArgument(b & 0x1f)
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborMajorUint:
+		if arg > math.MaxInt64 {
+			return arg, nil
+		}
+		return int64(arg), nil
+	case cborMajorNegInt:
+		if arg > math.MaxInt64 {
+			return nil, fmt.Errorf("CBOR negative integer out of range")
+		}
+		return -1 - int64(arg), nil
+	case cborMajorBytes, cborMajorString, cborMajorArray, cborMajorMap:
+		if arg > maxBinaryRecordSize {
+			return nil, fmt.Errorf("CBOR item too long: %d", arg)
+		}
+	}
+	switch major {
+	case cborMajorBytes:
+		return d.readBinary(arg)
+	case cborMajorString:
+		data, err := d.readBinary(arg)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	case cborMajorArray:
+		return d.readArray(arg)
+	case cborMajorMap:
+		return d.readMap(arg)
+	default:
+		return d.readTag(arg)
+	}
+}
+
+// readArgument reads the argument of a data item based on the additional information in the initial byte.
+func (d *cborDecoder) readArgument(info byte) (uint64, error) {
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info <= 27:
+		n := 1 << (info - 24)
+		data := make([]byte, 8)
+		if _, err := io.ReadFull(d.r, data[8-n:]); err != nil {
+			return 0, err
+		}
+		return binary.BigEndian.Uint64(data), nil
+	case info == 31:
+		return 0, fmt.Errorf("indefinite length CBOR items are not supported")
+	default:
+		return 0, fmt.Errorf("invalid CBOR additional information: %d", info)
+	}
+}
+
+func (d *cborDecoder) decodeSimple(info byte) (interface{}, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		v, err := d.readArgument(info)
+		return float64(halfToFloat32(uint16(v))), err
+	case 26:
+		v, err := d.readArgument(info)
+		return float64(math.Float32frombits(uint32(v))), err
+	case 27:
+		v, err := d.readArgument(info)
+		return math.Float64frombits(v), err
+	}
+	return nil, fmt.Errorf("unsupported CBOR simple value: %d", info)
+}
+
+func (d *cborDecoder) readBinary(l uint64) ([]byte, error) {
+	data := make([]byte, l)
+	if _, err := io.ReadFull(d.r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readArray decodes an array of l items. The slice grows as the items are read since the length is not trusted.
+func (d *cborDecoder) readArray(l uint64) (interface{}, error) {
+	result := []interface{}{}
+	for i := uint64(0); i < l; i++ {
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+func (d *cborDecoder) readMap(l uint64) (interface{}, error) {
+	result := make(map[string]interface{})
+	for i := uint64(0); i < l; i++ {
+		k, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		result[fmt.Sprintf("%v", k)] = v
+	}
+	return result, nil
+}
+
+// readTag decodes a tagged item. Date/time tags are converted to time.Time, other tags return the content.
+func (d *cborDecoder) readTag(tag uint64) (interface{}, error) {
+	content, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case cborTagDateTime:
+		if s, ok := content.(string); ok {
+			return time.Parse(time.RFC3339Nano, s)
+		}
+	case cborTagEpoch:
+		switch v := content.(type) {
+		case int64:
+			return time.Unix(v, 0), nil
+		case float64:
+			seconds, fraction := math.Modf(v)
+			return time.Unix(int64(seconds), int64(fraction*float64(time.Second))), nil
+		}
+	}
+	return content, nil
+}
+
+// halfToFloat32 converts an IEEE 754 half precision number to a float32.
+func halfToFloat32(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exponent := uint32(h>>10) & 0x1f
+	mantissa := uint32(h) & 0x3ff
+	switch exponent {
+	case 0:
+		value := float32(mantissa) / (1 << 24)
+		if sign != 0 {
+			value = -value
+		}
+		return value
+	case 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | mantissa<<13)
+	}
+	return math.Float32frombits(sign | (exponent+112)<<23 | mantissa<<13)
+}
@@ -0,0 +1,156 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+)
+
+var messageCodeChangelogTemplate = `# Message code changes
+{{ if .Empty }}
+No message codes were added, removed or changed.
+{{ end -}}
+{{ if .Added }}
+## Added codes
+
+| Code | Explanation |
+|------|-------------|
+{{ range .Added -}}
+| ` + "`{{ .Code }}`" + ` | {{ .Description }} |
+{{ end -}}
+{{ end -}}
+{{ if .Removed }}
+## Removed codes
+
+| Code | Explanation |
+|------|-------------|
+{{ range .Removed -}}
+| ` + "`{{ .Code }}`" + ` | {{ .Description }} |
+{{ end -}}
+{{ end -}}
+{{ if .Changed }}
+## Changed codes
+
+| Code | Field | Old value | New value |
+|------|-------|-----------|-----------|
+{{ range .Changed -}}
+{{ $code := .Code -}}
+{{ range .Fields -}}
+| ` + "`{{ $code }}`" + ` | {{ .Field }} | {{ .Old }} | {{ .New }} |
+{{ end -}}
+{{ end -}}
+{{ end -}}
+`
+
+// MessageCodeChange is a message code present in both catalogues with different metadata, e.g. a new description.
+type MessageCodeChange struct {
+	// Code is the message code that changed.
+	Code string `json:"code" yaml:"code"`
+	// Old is the code in the old catalogue.
+	Old MessageCodeInfo `json:"old" yaml:"old"`
+	// New is the code in the new catalogue.
+	New MessageCodeInfo `json:"new" yaml:"new"`
+	// Fields lists the fields that differ between the old and the new code.
+	Fields []MessageCodeFieldChange `json:"fields" yaml:"fields"`
+}
+
+// MessageCodeFieldChange is a single field of a message code that differs between two catalogues.
+type MessageCodeFieldChange struct {
+	// Field is the name of the field as used in JSON catalogues, e.g. description.
+	Field string `json:"field" yaml:"field"`
+	// Old is the value in the old catalogue.
+	Old string `json:"old" yaml:"old"`
+	// New is the value in the new catalogue.
+	New string `json:"new" yaml:"new"`
+}
+
+// MessageCodeChangelog lists the differences between two message code catalogues. All lists are sorted by code.
+type MessageCodeChangelog struct {
+	// Added contains the codes only present in the new catalogue.
+	Added []MessageCodeInfo `json:"added" yaml:"added"`
+	// Removed contains the codes only present in the old catalogue.
+	Removed []MessageCodeInfo `json:"removed" yaml:"removed"`
+	// Changed contains the codes present in both catalogues with different metadata.
+	Changed []MessageCodeChange `json:"changed" yaml:"changed"`
+}
+
+// Empty returns true if the catalogues are identical.
+func (c MessageCodeChangelog) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// CompareMessageCodeCatalogues returns the codes added, removed and changed between the old and the new catalogue.
+func CompareMessageCodeCatalogues(
+	oldCatalogue MessageCodeCatalogue,
+	newCatalogue MessageCodeCatalogue,
+) MessageCodeChangelog {
+	changelog := MessageCodeChangelog{
+		Added:   []MessageCodeInfo{},
+		Removed: []MessageCodeInfo{},
+		Changed: []MessageCodeChange{},
+	}
+	oldCodes := oldCatalogue.byCode()
+	newCodes := newCatalogue.byCode()
+	for _, newInfo := range newCatalogue.sorted().Codes {
+		oldInfo, ok := oldCodes[newInfo.Code]
+		if !ok {
+			changelog.Added = append(changelog.Added, newInfo)
+		} else if oldInfo != newInfo {
+			changelog.Changed = append(changelog.Changed, MessageCodeChange{
+				Code:   newInfo.Code,
+				Old:    oldInfo,
+				New:    newInfo,
+				Fields: changedFields(oldInfo, newInfo),
+			})
+		}
+	}
+	for _, oldInfo := range oldCatalogue.sorted().Codes {
+		if _, ok := newCodes[oldInfo.Code]; !ok {
+			changelog.Removed = append(changelog.Removed, oldInfo)
+		}
+	}
+	return changelog
+}
+
+func changedFields(oldInfo MessageCodeInfo, newInfo MessageCodeInfo) []MessageCodeFieldChange {
+	fields := []MessageCodeFieldChange{
+		{"description", oldInfo.Description, newInfo.Description},
+		{"level", string(oldInfo.Level), string(newInfo.Level)},
+		{"userMessage", oldInfo.UserMessage, newInfo.UserMessage},
+		{"documentationUrl", oldInfo.DocumentationURL, newInfo.DocumentationURL},
+		{"owner", oldInfo.Owner, newInfo.Owner},
+		{"severity", oldInfo.Severity, newInfo.Severity},
+		{"resolution", oldInfo.Resolution, newInfo.Resolution},
+		{"since", oldInfo.Since, newInfo.Since},
+	}
+	var result []MessageCodeFieldChange
+	for _, field := range fields {
+		if field.Old != field.New {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+// GenerateMessageCodeChangelogDocument renders the changelog as a Markdown document.
+func GenerateMessageCodeChangelogDocument(changelog MessageCodeChangelog) (string, error) {
+	tpl, err := template.New("CHANGES.md.tpl").Parse(messageCodeChangelogTemplate)
+	if err != nil {
+		return "", fmt.Errorf("bug: failed to parse template (%w)", err)
+	}
+	wr := &bytes.Buffer{}
+	if err := tpl.Execute(wr, changelog); err != nil {
+		return "", fmt.Errorf("failed to render changelog template (%w)", err)
+	}
+	return wr.String(), nil
+}
+
+// GenerateMessageCodeChangelogJSON renders the changelog as indented JSON.
+func GenerateMessageCodeChangelogJSON(changelog MessageCodeChangelog) (string, error) {
+	data, err := json.MarshalIndent(changelog, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode message code changelog (%w)", err)
+	}
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,88 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func TestCompareMessageCodeCatalogues(t *testing.T) {
+	oldCatalogue := log.MessageCodeCatalogue{Codes: []log.MessageCodeInfo{
+		{Code: "REMOVED", Description: "This code was removed."},
+		{Code: "CHANGED", Description: "Old description."},
+		{Code: "UNCHANGED", Description: "This code did not change."},
+	}}
+	newCatalogue := log.MessageCodeCatalogue{Codes: []log.MessageCodeInfo{
+		{Code: "UNCHANGED", Description: "This code did not change."},
+		{Code: "CHANGED", Description: "New description.", Since: "0.5.0"},
+		{Code: "ADDED", Description: "This code was added."},
+	}}
+	changelog := log.CompareMessageCodeCatalogues(oldCatalogue, newCatalogue)
+	assert.False(t, changelog.Empty())
+	assert.Equal(t, []log.MessageCodeInfo{{Code: "ADDED", Description: "This code was added."}}, changelog.Added)
+	assert.Equal(t, []log.MessageCodeInfo{{Code: "REMOVED", Description: "This code was removed."}}, changelog.Removed)
+	if !assert.Len(t, changelog.Changed, 1) {
+		return
+	}
+	assert.Equal(t, "CHANGED", changelog.Changed[0].Code)
+	assert.Equal(t, []log.MessageCodeFieldChange{
+		{Field: "description", Old: "Old description.", New: "New description."},
+		{Field: "since", Old: "", New: "0.5.0"},
+	}, changelog.Changed[0].Fields)
+
+	assert.True(t, log.CompareMessageCodeCatalogues(newCatalogue, newCatalogue).Empty())
+}
+
+func TestGenerateMessageCodeChangelogDocument(t *testing.T) {
+	document, err := log.GenerateMessageCodeChangelogDocument(log.MessageCodeChangelog{
+		Added:   []log.MessageCodeInfo{{Code: "ADDED", Description: "This code was added."}},
+		Removed: []log.MessageCodeInfo{{Code: "REMOVED", Description: "This code was removed."}},
+		Changed: []log.MessageCodeChange{{
+			Code: "CHANGED",
+			Fields: []log.MessageCodeFieldChange{
+				{Field: "description", Old: "Old description.", New: "New description."},
+				{Field: "since", Old: "", New: "0.5.0"},
+			},
+		}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "# Message code changes\n\n"+
+		"## Added codes\n\n"+
+		"| Code | Explanation |\n"+
+		"|------|-------------|\n"+
+		"| `ADDED` | This code was added. |\n\n"+
+		"## Removed codes\n\n"+
+		"| Code | Explanation |\n"+
+		"|------|-------------|\n"+
+		"| `REMOVED` | This code was removed. |\n\n"+
+		"## Changed codes\n\n"+
+		"| Code | Field | Old value | New value |\n"+
+		"|------|-------|-----------|-----------|\n"+
+		"| `CHANGED` | description | Old description. | New description. |\n"+
+		"| `CHANGED` | since |  | 0.5.0 |\n", document)
+
+	document, err = log.GenerateMessageCodeChangelogDocument(log.MessageCodeChangelog{})
+	assert.NoError(t, err)
+	assert.Equal(t, "# Message code changes\n\nNo message codes were added, removed or changed.\n", document)
+}
+
+func TestParseMessageCodeCatalogueJSON(t *testing.T) {
+	original := log.MessageCodeCatalogue{Codes: []log.MessageCodeInfo{
+		{Code: "UNCHANGED", Description: "This code did not change."},
+		{Code: "CHANGED", Description: "New description.", Since: "0.5.0"},
+		{Code: "ADDED", Description: "This code was added."},
+	}}
+	data, err := log.GenerateMessageCodeCatalogueJSON(original)
+	assert.NoError(t, err)
+	catalogue, err := log.ParseMessageCodeCatalogueJSON([]byte(data))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ADDED", "CHANGED", "UNCHANGED"}, []string{
+		catalogue.Codes[0].Code, catalogue.Codes[1].Code, catalogue.Codes[2].Code,
+	})
+	assert.True(t, log.CompareMessageCodeCatalogues(original, catalogue).Empty())
+
+	_, err = log.ParseMessageCodeCatalogueJSON([]byte("{"))
+	assert.Error(t, err)
+}
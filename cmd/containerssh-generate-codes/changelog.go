@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containerssh/log"
+	"github.com/containerssh/log/codes"
+)
+
+const changelogCommandUsage = "Usage: containerssh-generate-codes changelog [-type TYPE] [-prefix E,M,W] [-module] " +
+	"[-format markdown|json] [-fail-on-removed] OLD NEW\n\n" +
+	"Compares two message code catalogues and prints the codes that were added, removed or changed. OLD and NEW may " +
+	"be Go files, package directories, or JSON catalogues written with -format json.\n\n"
+
+type changelogOptions struct {
+	filter        log.MessageCodeFilter
+	module        bool
+	format        string
+	failOnRemoved bool
+	oldSource     string
+	newSource     string
+}
+
+// runChangelog compares two catalogues and returns the exit code. The exit code is 1 on errors, or if codes were
+// removed and -fail-on-removed is set.
+func runChangelog(args []string) int {
+	opts := parseChangelogOptions(args)
+	oldCatalogue, err := loadCatalogue(opts.oldSource, opts.module, opts.filter)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	newCatalogue, err := loadCatalogue(opts.newSource, opts.module, opts.filter)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	changelog := log.CompareMessageCodeCatalogues(oldCatalogue, newCatalogue)
+	var data string
+	switch opts.format {
+	case formatMarkdown:
+		data, err = log.GenerateMessageCodeChangelogDocument(changelog)
+	case formatJSON:
+		data, err = log.GenerateMessageCodeChangelogJSON(changelog)
+	default:
+		err = fmt.Errorf("output format not supported by changelog: %s", opts.format)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Print(data)
+	if opts.failOnRemoved && len(changelog.Removed) > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "%d message code(s) were removed.\n", len(changelog.Removed))
+		return 1
+	}
+	return 0
+}
+
+func parseChangelogOptions(args []string) changelogOptions {
+	flags := flag.NewFlagSet("containerssh-generate-codes changelog", flag.ExitOnError)
+	flags.Usage = func() {
+		_, _ = fmt.Fprint(flags.Output(), changelogCommandUsage)
+		flags.PrintDefaults()
+	}
+	filter := filterFlags(flags)
+	module := flags.Bool("module", false, "Scan all packages of the Go modules in the OLD and NEW directories.")
+	format := flags.String("format", formatMarkdown, "Output format: markdown or json.")
+	failOnRemoved := flags.Bool("fail-on-removed", false, "Exit with a non-zero exit code if codes were removed.")
+	_ = flags.Parse(args)
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(1)
+	}
+	return changelogOptions{
+		filter:        filter(),
+		module:        *module,
+		format:        *format,
+		failOnRemoved: *failOnRemoved,
+		oldSource:     flags.Arg(0),
+		newSource:     flags.Arg(1),
+	}
+}
+
+// loadCatalogue reads a JSON catalogue, or extracts the codes from a Go file, package or module.
+func loadCatalogue(source string, module bool, filter log.MessageCodeFilter) (log.MessageCodeCatalogue, error) {
+	stat, err := os.Stat(source)
+	if err != nil {
+		return log.MessageCodeCatalogue{}, err
+	}
+	switch {
+	case module:
+		return codes.GetModuleMessageCodeCatalogue(source, filter)
+	case stat.IsDir():
+		return codes.GetPackageMessageCodeCatalogue(source, filter)
+	case strings.HasSuffix(source, ".json"):
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return log.MessageCodeCatalogue{}, err
+		}
+		return log.ParseMessageCodeCatalogueJSON(data)
+	default:
+		return log.GetFileMessageCodeCatalogue(source, filter)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const diffContext = 3
+
+// noNewlineMarker is appended to the last line of a text without a trailing newline so it never matches a terminated
+// line. It is written as the "\ No newline at end of file" line of the diff.
+const noNewlineMarker = "\x00"
+
+// diffOp is a single line in a diff. kind is ' ' for unchanged lines, '-' for removed and '+' for added lines. aPos
+// and bPos are the number of lines consumed from the old and new text before this line.
+type diffOp struct {
+	kind byte
+	text string
+	aPos int
+	bPos int
+}
+
+// unifiedDiff returns a unified diff between the old and new text, or an empty string if they are identical.
+func unifiedDiff(oldName string, oldText string, newName string, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+	result := &strings.Builder{}
+	_, _ = fmt.Fprintf(result, "--- %s\n+++ %s\n", oldName, newName)
+	for start := 0; start < len(ops); {
+		first := nextChange(ops, start)
+		if first < 0 {
+			break
+		}
+		hunkStart := maxInt(first-diffContext, start)
+		hunkEnd := hunkEndFrom(ops, first)
+		writeHunk(result, ops[hunkStart:hunkEnd])
+		start = hunkEnd
+	}
+	return result.String()
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	if !strings.HasSuffix(text, "\n") {
+		return strings.Split(text+noNewlineMarker, "\n")
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines computes the line operations using the longest common subsequence of the two texts.
+func diffLines(a []string, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i], i, j})
+			i++
+			j++
+		case j >= len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', a[i], i, j})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j], i, j})
+			j++
+		}
+	}
+	return ops
+}
+
+func nextChange(ops []diffOp, start int) int {
+	for i := start; i < len(ops); i++ {
+		if ops[i].kind != ' ' {
+			return i
+		}
+	}
+	return -1
+}
+
+// hunkEndFrom returns the end of the hunk starting with the change at index first. Changes separated by less than
+// twice the context are merged into one hunk.
+func hunkEndFrom(ops []diffOp, first int) int {
+	lastChange := first
+	for i := first + 1; i < len(ops) && i <= lastChange+2*diffContext; i++ {
+		if ops[i].kind != ' ' {
+			lastChange = i
+		}
+	}
+	return minInt(lastChange+diffContext+1, len(ops))
+}
+
+func writeHunk(result *strings.Builder, hunk []diffOp) {
+	aLen, bLen := 0, 0
+	for _, op := range hunk {
+		if op.kind != '+' {
+			aLen++
+		}
+		if op.kind != '-' {
+			bLen++
+		}
+	}
+	_, _ = fmt.Fprintf(
+		result,
+		"@@ -%s +%s @@\n",
+		hunkRange(hunk[0].aPos, aLen),
+		hunkRange(hunk[0].bPos, bLen),
+	)
+	for _, op := range hunk {
+		result.WriteByte(op.kind)
+		result.WriteString(strings.TrimSuffix(op.text, noNewlineMarker))
+		result.WriteByte('\n')
+		if strings.HasSuffix(op.text, noNewlineMarker) {
+			result.WriteString("\\ No newline at end of file\n")
+		}
+	}
+}
+
+func hunkRange(pos int, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", pos)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", pos+1)
+	}
+	return fmt.Sprintf("%d,%d", pos+1, length)
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiffIdentical(t *testing.T) {
+	assert.Equal(t, "", unifiedDiff("a", "foo\nbar\n", "b", "foo\nbar\n"))
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	oldText := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n"
+	newText := "1\n2\n3\n4\nfive\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n"
+	assert.Equal(
+		t,
+		"--- CODES.md\n"+
+			"+++ CODES.md (generated)\n"+
+			"@@ -2,7 +2,7 @@\n"+
+			" 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n"+
+			"@@ -13,3 +13,4 @@\n"+
+			" 13\n 14\n 15\n+16\n",
+		unifiedDiff("CODES.md", oldText, "CODES.md (generated)", newText),
+	)
+}
+
+func TestUnifiedDiffEmptyOld(t *testing.T) {
+	assert.Equal(
+		t,
+		"--- a\n+++ b\n@@ -0,0 +1,2 @@\n+foo\n+bar\n",
+		unifiedDiff("a", "", "b", "foo\nbar\n"),
+	)
+}
+
+func TestUnifiedDiffMissingTrailingNewline(t *testing.T) {
+	assert.Equal(
+		t,
+		"--- a\n+++ b\n@@ -1,2 +1,2 @@\n foo\n-bar\n\\ No newline at end of file\n+bar\n",
+		unifiedDiff("a", "foo\nbar", "b", "foo\nbar\n"),
+	)
+	assert.Equal(
+		t,
+		"--- a\n+++ b\n@@ -1,2 +1,2 @@\n foo\n-bar\n+bar\n\\ No newline at end of file\n",
+		unifiedDiff("a", "foo\nbar\n", "b", "foo\nbar"),
+	)
+}
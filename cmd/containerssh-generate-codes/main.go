@@ -1,28 +1,230 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"gopkg.in/yaml.v3"
 
 	"github.com/containerssh/log"
+	"github.com/containerssh/log/codes"
 )
 
-func main() {
-	source := "codes.go"
-	destination := "CODES.md"
+const usage = "Usage: containerssh-generate-codes [-type TYPE] [-prefix E,M,W] [-module] [-check] " +
+	"[-format markdown|json|yaml|go] [-package NAME] [-template FILE] [source DESTINATION]\n" +
+	"       containerssh-generate-codes changelog [-type TYPE] [-prefix E,M,W] [-module] [-format markdown|json] " +
+	"[-fail-on-removed] OLD NEW\n" +
+	"       containerssh-generate-codes -merge [-module] [-check] [-format markdown|json|yaml] " +
+	"SOURCE... DESTINATION\n\n" +
+	"The source may be a Go file or a package directory. With -module all packages below the source directory are " +
+	"scanned. With -merge the codes of several packages or modules are combined into one catalogue and duplicate " +
+	"codes are reported. Merge sources may be directories, or package or module paths resolved using go list.\n\n"
+
+const (
+	formatMarkdown = "markdown"
+	formatJSON     = "json"
+	formatYAML     = "yaml"
+	formatGo       = "go"
+)
+
+type options struct {
+	source      string
+	destination string
+	filter      log.MessageCodeFilter
+	module      bool
+	check       bool
+	format      string
+	packageName string
+	merge       bool
+	sources     []string
+	template    string
+}
 
+func main() {
 	if len(os.Args) > 1 {
-		if len(os.Args) == 2 && (os.Args[1] == "-h" || os.Args[1] == "--help") {
-			println("Usage: containerssh-generate-codes [source.go DESTINATION.md]")
-			os.Exit(0)
-		} else if len(os.Args) != 3 {
-			println("Usage: containerssh-generate-codes [source.go DESTINATION.md]")
-			os.Exit(1)
+		switch os.Args[1] {
+		case "changelog":
+			os.Exit(runChangelog(os.Args[2:]))
+		}
+	}
+	opts := parseOptions(os.Args[1:])
+	data, err := render(opts)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if opts.check {
+		os.Exit(checkDestination(opts.destination, data))
+	}
+	if err := os.WriteFile(opts.destination, []byte(data), 0644); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to write destination file %s (%v)\n", opts.destination, err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s successfully written.\n", opts.destination)
+}
+
+func parseOptions(args []string) options {
+	flags := flag.NewFlagSet("containerssh-generate-codes", flag.ExitOnError)
+	flags.Usage = func() {
+		_, _ = fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	filter := filterFlags(flags)
+	module := flags.Bool("module", false, "Scan all packages of the Go module in the source directory.")
+	check := flags.Bool("check", false, "Do not write the destination, exit with an error if it is not up to date.")
+	format := flags.String("format", formatMarkdown, "Output format: markdown, json, yaml or go.")
+	packageName := flags.String("package", "", "Package name of the generated Go file. (default: source package)")
+	merge := flags.Bool("merge", false, "Merge the codes of multiple sources and report duplicates.")
+	templateFile := flags.String("template", "", "Render the Markdown output using this text/template file.")
+	_ = flags.Parse(args)
+
+	opts := options{
+		source:      "codes.go",
+		destination: "CODES.md",
+		filter:      filter(),
+		module:      *module,
+		check:       *check,
+		format:      *format,
+		packageName: *packageName,
+		merge:       *merge,
+		template:    *templateFile,
+	}
+	switch {
+	case opts.merge && flags.NArg() >= 2:
+		opts.sources = flags.Args()[:flags.NArg()-1]
+		opts.destination = flags.Arg(flags.NArg() - 1)
+	case opts.merge:
+		flags.Usage()
+		os.Exit(1)
+	case flags.NArg() == 0:
+	case flags.NArg() == 2:
+		opts.source = flags.Arg(0)
+		opts.destination = flags.Arg(1)
+	default:
+		flags.Usage()
+		os.Exit(1)
+	}
+	return opts
+}
+
+// filterFlags registers the -type and -prefix flags and returns a function that builds the filter after parsing.
+func filterFlags(flags *flag.FlagSet) func() log.MessageCodeFilter {
+	typeName := flags.String("type", "", "Only include constants declared with this type.")
+	prefixes := flags.String("prefix", "", "Comma-separated constant name prefixes to include. (default \"E,M,W\")")
+	return func() log.MessageCodeFilter {
+		filter := log.MessageCodeFilter{TypeName: *typeName}
+		if *prefixes != "" {
+			filter.Prefixes = strings.Split(*prefixes, ",")
+		}
+		return filter
+	}
+}
+
+// checkDestination compares the destination file with the generated contents and prints a diff if they differ.
+func checkDestination(destination string, data string) int {
+	existing, err := os.ReadFile(destination)
+	if err != nil && !os.IsNotExist(err) {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to read %s (%v)\n", destination, err)
+		return 1
+	}
+	diff := unifiedDiff(destination, string(existing), destination+" (generated)", data)
+	if diff == "" {
+		fmt.Printf("%s is up to date.\n", destination)
+		return 0
+	}
+	fmt.Print(diff)
+	_, _ = fmt.Fprintf(os.Stderr, "%s is out of date, please run containerssh-generate-codes.\n", destination)
+	return 1
+}
+
+// render generates the contents of the destination file in the selected format. A single Markdown file with the
+// default filter uses the original file based generator.
+func render(opts options) (string, error) {
+	if opts.merge {
+		return renderMerged(opts)
+	}
+	stat, err := os.Stat(opts.source)
+	if err != nil {
+		return "", err
+	}
+	defaultFilter := opts.filter.TypeName == "" && len(opts.filter.Prefixes) == 0
+	if opts.format == formatMarkdown && opts.template == "" && !opts.module && !stat.IsDir() && defaultFilter {
+		return log.GenerateMessageCodesFile(opts.source)
+	}
+	catalogue, err := getCatalogue(opts, stat.IsDir())
+	if err != nil {
+		return "", err
+	}
+	switch opts.format {
+	case formatMarkdown:
+		return renderMarkdown(opts, catalogue)
+	case formatJSON:
+		return log.GenerateMessageCodeCatalogueJSON(catalogue)
+	case formatYAML:
+		data, err := yaml.Marshal(catalogue)
+		return string(data), err
+	case formatGo:
+		packageName, importPath, err := sourcePackage(opts.source, stat.IsDir(), opts.packageName)
+		if err != nil {
+			return "", err
 		}
-		source = os.Args[1]
-		destination = os.Args[2]
+		return log.GenerateMessageCodesRegistry(catalogue, packageName, importPath)
+	default:
+		return "", fmt.Errorf("invalid output format: %s", opts.format)
+	}
+}
+
+func getCatalogue(opts options, dir bool) (log.MessageCodeCatalogue, error) {
+	if opts.module {
+		return codes.GetModuleMessageCodeCatalogue(opts.source, opts.filter)
+	}
+	if dir {
+		return codes.GetPackageMessageCodeCatalogue(opts.source, opts.filter)
 	}
+	return log.GetFileMessageCodeCatalogue(opts.source, opts.filter)
+}
+
+// renderMarkdown renders the catalogue using the default CODES.md layout or the template file passed in -template.
+func renderMarkdown(opts options, catalogue log.MessageCodeCatalogue) (string, error) {
+	if opts.template == "" {
+		return log.GenerateMessageCodesDocument(descriptions(catalogue))
+	}
+	templateText, err := os.ReadFile(opts.template)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template file %s (%w)", opts.template, err)
+	}
+	return log.GenerateMessageCodesDocumentWithTemplate(catalogue, string(templateText))
+}
+
+// descriptions returns the descriptions of the codes in the catalogue keyed by code.
+func descriptions(catalogue log.MessageCodeCatalogue) map[string]string {
+	result := make(map[string]string, len(catalogue.Codes))
+	for _, info := range catalogue.Codes {
+		result[info.Code] = info.Description
+	}
+	return result
+}
 
-	log.MustWriteMessageCodesFile(source, destination)
-	fmt.Printf("%s successfully written.\n", destination)
+// sourcePackage returns the name and import path of the package containing the source file or directory. If a
+// package name is passed in -package, it is used instead of the name of the source package. The import path is empty
+// if the source is not part of a module.
+func sourcePackage(source string, dir bool, packageName string) (string, string, error) {
+	if !dir {
+		source = filepath.Dir(source)
+	}
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName, Dir: source}, ".")
+	if err != nil || len(pkgs) != 1 || pkgs[0].Name == "" {
+		if packageName != "" {
+			return packageName, "", nil
+		}
+		return "", "", fmt.Errorf("cannot determine package name in %s, please pass -package", source)
+	}
+	if packageName == "" {
+		packageName = pkgs[0].Name
+	}
+	return packageName, pkgs[0].PkgPath, nil
 }
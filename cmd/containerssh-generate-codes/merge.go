@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/containerssh/log"
+	"github.com/containerssh/log/codes"
+)
+
+// renderMerged merges the codes of all sources and renders them in the selected format. Duplicate codes fail the
+// generation.
+func renderMerged(opts options) (string, error) {
+	sources := make([]codes.MessageCodeSource, len(opts.sources))
+	for i, sourcePath := range opts.sources {
+		source, err := resolveSource(sourcePath, opts.module)
+		if err != nil {
+			return "", err
+		}
+		sources[i] = source
+	}
+	catalogue, err := codes.MergeMessageCodes(sources, opts.filter)
+	if err != nil {
+		return "", err
+	}
+	switch opts.format {
+	case formatMarkdown:
+		if opts.template != "" {
+			return renderMarkdown(opts, catalogue)
+		}
+		return log.GenerateMergedMessageCodesDocument(catalogue)
+	case formatJSON:
+		return log.GenerateMessageCodeCatalogueJSON(catalogue)
+	case formatYAML:
+		data, err := yaml.Marshal(catalogue)
+		return string(data), err
+	default:
+		return "", fmt.Errorf("output format not supported with -merge: %s", opts.format)
+	}
+}
+
+// resolveSource returns the source for a directory, or looks up the directory of a package or module path using
+// go list. For package and module paths the path is used as the owner.
+func resolveSource(sourcePath string, module bool) (codes.MessageCodeSource, error) {
+	if stat, err := os.Stat(sourcePath); err == nil && stat.IsDir() {
+		return codes.MessageCodeSource{Path: sourcePath, Module: module}, nil
+	}
+	args := []string{"list", "-f", "{{.Dir}}", sourcePath}
+	if module {
+		args = []string{"list", "-m", "-f", "{{.Dir}}", sourcePath}
+	}
+	output, err := exec.Command("go", args...).Output()
+	if err != nil {
+		return codes.MessageCodeSource{}, fmt.Errorf("failed to resolve %s using go list (%w)", sourcePath, err)
+	}
+	dir := strings.TrimSpace(string(output))
+	if dir == "" {
+		return codes.MessageCodeSource{}, fmt.Errorf("%s is not downloaded, please run go mod download", sourcePath)
+	}
+	return codes.MessageCodeSource{Path: dir, Owner: sourcePath, Module: module}, nil
+}
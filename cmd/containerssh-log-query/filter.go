@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containerssh/log"
+)
+
+// entryFilter selects the log entries matching all configured criteria.
+type entryFilter struct {
+	// mostSevere and leastSevere limit the level range. LevelEmergency is the most severe level.
+	mostSevere  log.Level
+	leastSevere log.Level
+	codes       []string
+	since       time.Time
+	until       time.Time
+	labels      []labelPredicate
+}
+
+func (f entryFilter) matches(entry log.LogEntry) bool {
+	if entry.Level < f.mostSevere || entry.Level > f.leastSevere {
+		return false
+	}
+	if !f.since.IsZero() && entry.Time.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && entry.Time.After(f.until) {
+		return false
+	}
+	if len(f.codes) > 0 && !matchesAnyGlob(f.codes, entry.Message.Code()) {
+		return false
+	}
+	for _, predicate := range f.labels {
+		if !predicate.matches(entry.Message.Labels()) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAnyGlob(globs []string, code string) bool {
+	for _, glob := range globs {
+		if matched, _ := path.Match(glob, code); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLevelRange parses a single level, matching this level and all more severe ones like the logger level, or a
+// range of two levels separated by a colon, e.g. debug:notice.
+func parseLevelRange(value string) (log.Level, log.Level, error) {
+	if value == "" {
+		return log.LevelEmergency, log.LevelDebug, nil
+	}
+	parts := strings.SplitN(value, ":", 2)
+	first, err := log.LevelString(parts[0]).ToLevel()
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(parts) == 1 {
+		return log.LevelEmergency, first, nil
+	}
+	second, err := log.LevelString(parts[1]).ToLevel()
+	if err != nil {
+		return 0, 0, err
+	}
+	if first > second {
+		first, second = second, first
+	}
+	return first, second, nil
+}
+
+// parseTime parses an RFC 3339 timestamp, or a duration relative to the current time, e.g. 1h for one hour ago.
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %s, expected an RFC 3339 timestamp or a duration", value)
+	}
+	return timestamp, nil
+}
+
+// labelOperators are the supported label comparisons. Longer operators come first so they are matched before their
+// prefixes.
+var labelOperators = []string{"!=", ">=", "<=", "=", "~", ">", "<"}
+
+// labelPredicate compares a label with a value, e.g. username=foo, remoteAddr~^10\. or port>=1024.
+type labelPredicate struct {
+	name     log.LabelName
+	operator string
+	value    string
+	pattern  *regexp.Regexp
+	number   float64
+}
+
+func parseLabelPredicate(expression string) (labelPredicate, error) {
+	index := strings.IndexAny(expression, "!=~<>")
+	if index <= 0 {
+		return labelPredicate{}, fmt.Errorf("invalid label expression %s, expected e.g. name=value", expression)
+	}
+	predicate := labelPredicate{name: log.LabelName(expression[:index])}
+	for _, operator := range labelOperators {
+		if strings.HasPrefix(expression[index:], operator) {
+			predicate.operator = operator
+			break
+		}
+	}
+	if predicate.operator == "" {
+		return labelPredicate{}, fmt.Errorf("invalid operator in label expression %s", expression)
+	}
+	predicate.value = expression[index+len(predicate.operator):]
+	var err error
+	switch predicate.operator {
+	case "~":
+		predicate.pattern, err = regexp.Compile(predicate.value)
+	case ">", ">=", "<", "<=":
+		predicate.number, err = strconv.ParseFloat(predicate.value, 64)
+	}
+	if err != nil {
+		return labelPredicate{}, fmt.Errorf("invalid value in label expression %s (%w)", expression, err)
+	}
+	return predicate, nil
+}
+
+func (p labelPredicate) matches(labels log.Labels) bool {
+	value, ok := labels[p.name]
+	if !ok {
+		return p.operator == "!="
+	}
+	formatted := fmt.Sprintf("%v", value)
+	switch p.operator {
+	case "=":
+		return formatted == p.value
+	case "!=":
+		return formatted != p.value
+	case "~":
+		return p.pattern.MatchString(formatted)
+	}
+	number, err := strconv.ParseFloat(formatted, 64)
+	if err != nil {
+		return false
+	}
+	switch p.operator {
+	case ">":
+		return number > p.number
+	case ">=":
+		return number >= p.number
+	case "<":
+		return number < p.number
+	default:
+		return number <= p.number
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func TestLabelPredicates(t *testing.T) {
+	labels := log.Labels{"username": "foo", "port": int64(2222), "ratio": 0.5}
+	for expression, expected := range map[string]bool{
+		"username=foo":      true,
+		"username=bar":      false,
+		"username!=bar":     true,
+		"missing!=bar":      true,
+		"missing=bar":       false,
+		"username~^f.o$":    true,
+		"username~^bar":     false,
+		"port>1024":         true,
+		"port>=2222":        true,
+		"port<2222":         false,
+		"port<=2222":        true,
+		"ratio<1":           true,
+		"username>1":        false,
+		"port=2222":         true,
+		"remoteAddr~^10\\.": false,
+	} {
+		predicate, err := parseLabelPredicate(expression)
+		if !assert.NoError(t, err, expression) {
+			continue
+		}
+		assert.Equal(t, expected, predicate.matches(labels), expression)
+	}
+}
+
+func TestInvalidLabelPredicates(t *testing.T) {
+	for _, expression := range []string{"username", "=foo", "port>abc", "username~(", "username!foo"} {
+		_, err := parseLabelPredicate(expression)
+		assert.Error(t, err, expression)
+	}
+}
+
+func TestParseLevelRange(t *testing.T) {
+	mostSevere, leastSevere, err := parseLevelRange("warning")
+	assert.NoError(t, err)
+	assert.Equal(t, log.LevelEmergency, mostSevere)
+	assert.Equal(t, log.LevelWarning, leastSevere)
+
+	mostSevere, leastSevere, err = parseLevelRange("debug:notice")
+	assert.NoError(t, err)
+	assert.Equal(t, log.LevelNotice, mostSevere)
+	assert.Equal(t, log.LevelDebug, leastSevere)
+
+	_, _, err = parseLevelRange("loud")
+	assert.Error(t, err)
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	since, err := parseTime("1h", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 3, 4, 4, 6, 7, 0, time.UTC), since)
+
+	since, err = parseTime("2021-03-01T00:00:00Z", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), since)
+
+	_, err = parseTime("yesterday", now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/containerssh/structutils"
+	"gopkg.in/yaml.v3"
+
+	"github.com/containerssh/log"
+)
+
+const usage = "Usage: containerssh-log-query [-level LEVEL[:LEVEL]] [-code GLOB]... [-since TIME] [-until TIME] " +
+	"[-label EXPRESSION]... [-format FORMAT] [-summary code|level|label:NAME] [FILE...]\n\n" +
+	"Filters log files written in the ljson format and writes the matching messages in the selected format, or " +
+	"counts them with -summary. If no file is given, the logs are read from the standard input.\n\n" +
+	"Label expressions compare a label with a value: name=value, name!=value, name~regexp, or a numeric comparison " +
+	"with name>N, name>=N, name<N and name<=N. TIME is an RFC 3339 timestamp or a duration before now, e.g. 1h.\n\n"
+
+// stringList is a flag that can be passed multiple times.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+type options struct {
+	filter       entryFilter
+	summary      string
+	format       string
+	inputConfig  string
+	outputConfig string
+	files        []string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, time.Now()))
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, now time.Time) int {
+	opts, err := parseOptions(args, now)
+	if err == nil {
+		err = query(opts, stdin, stdout)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func parseOptions(args []string, now time.Time) (options, error) {
+	var codes, labels stringList
+	flags := flag.NewFlagSet("containerssh-log-query", flag.ExitOnError)
+	flags.Usage = func() {
+		_, _ = fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	level := flags.String("level", "", "Level or more severe, or a range of levels separated by a colon.")
+	flags.Var(&codes, "code", "Message code glob, e.g. LOG_*. May be passed multiple times.")
+	since := flags.String("since", "", "Only messages logged at or after this time.")
+	until := flags.String("until", "", "Only messages logged at or before this time.")
+	flags.Var(&labels, "label", "Label expression all messages must match. May be passed multiple times.")
+	format := flags.String("format", "", "Output format, e.g. ljson, text or console. (default: ljson)")
+	summary := flags.String("summary", "", "Print the number of matching messages by code, level or label:NAME.")
+	inputConfig := flags.String("config", "", "YAML or JSON logger configuration describing a customized ljson input.")
+	outputConfig := flags.String("output-config", "", "YAML or JSON logger configuration for the output format.")
+	_ = flags.Parse(args)
+
+	opts := options{
+		filter:       entryFilter{codes: codes},
+		summary:      *summary,
+		format:       *format,
+		inputConfig:  *inputConfig,
+		outputConfig: *outputConfig,
+		files:        flags.Args(),
+	}
+	var err error
+	if opts.filter.mostSevere, opts.filter.leastSevere, err = parseLevelRange(*level); err != nil {
+		return opts, err
+	}
+	if opts.filter.since, err = parseTime(*since, now); err != nil {
+		return opts, err
+	}
+	if opts.filter.until, err = parseTime(*until, now); err != nil {
+		return opts, err
+	}
+	for _, expression := range labels {
+		predicate, err := parseLabelPredicate(expression)
+		if err != nil {
+			return opts, err
+		}
+		opts.filter.labels = append(opts.filter.labels, predicate)
+	}
+	return opts, nil
+}
+
+// query decodes all inputs and passes the matching entries to the summary or the output logger.
+func query(opts options, stdin io.Reader, stdout io.Writer) error {
+	inputConfig := log.Config{}
+	if err := readConfig(opts.inputConfig, &inputConfig); err != nil {
+		return err
+	}
+	input, err := openInput(opts.files, stdin)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = input.Close()
+	}()
+	var process func(entry log.LogEntry) error
+	var finish func() error
+	if opts.summary != "" {
+		process, finish, err = newSummaryOutput(opts.summary, stdout)
+	} else {
+		process, finish, err = newLogOutput(opts, stdout)
+	}
+	if err != nil {
+		return err
+	}
+	if err := decodeAll(log.NewLJSONDecoder(input, inputConfig), opts.filter, process); err != nil {
+		_ = finish()
+		return err
+	}
+	return finish()
+}
+
+func decodeAll(decoder log.Decoder, filter entryFilter, process func(entry log.LogEntry) error) error {
+	for {
+		entry, err := decoder.Decode()
+		var lineError *log.LineDecodeError
+		switch {
+		case err == nil:
+			if !filter.matches(entry) {
+				continue
+			}
+			if err := process(entry); err != nil {
+				return err
+			}
+		case errors.As(err, &lineError):
+			_, _ = fmt.Fprintf(os.Stderr, "skipping %v\n", lineError)
+		case errors.Is(err, io.EOF):
+			return nil
+		default:
+			return err
+		}
+	}
+}
+
+func newSummaryOutput(by string, stdout io.Writer) (func(entry log.LogEntry) error, func() error, error) {
+	s, err := newSummary(by)
+	if err != nil {
+		return nil, nil, err
+	}
+	return func(entry log.LogEntry) error {
+		s.add(entry)
+		return nil
+	}, func() error {
+		return s.write(stdout)
+	}, nil
+}
+
+// newLogOutput creates a logger writing the entries in the output format. The logger clock returns the time of the
+// entry being written so the original timestamps are kept.
+func newLogOutput(opts options, stdout io.Writer) (func(entry log.LogEntry) error, func() error, error) {
+	config := log.Config{}
+	structutils.Defaults(&config)
+	if err := readConfig(opts.outputConfig, &config); err != nil {
+		return nil, nil, err
+	}
+	if opts.format != "" {
+		config.Format = log.Format(opts.format)
+	}
+	var current time.Time
+	config.Level = log.LevelDebug
+	config.Destination = log.DestinationStdout
+	config.Stdout = stdout
+	config.Clock = func() time.Time {
+		return current
+	}
+	logger, err := log.NewLogger(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return func(entry log.LogEntry) error {
+		current = entry.Time
+		return log.LogAtLevel(logger, entry.Level, entry.Message)
+	}, logger.Close, nil
+}
+
+func readConfig(file string, config *log.Config) error {
+	if file == "" {
+		return nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file %s (%w)", file, err)
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return fmt.Errorf("failed to parse configuration file %s (%w)", file, err)
+	}
+	return nil
+}
+
+// openInput returns a reader reading all files in order, or the standard input if no files are given.
+func openInput(files []string, stdin io.Reader) (io.ReadCloser, error) {
+	if len(files) == 0 {
+		return io.NopCloser(stdin), nil
+	}
+	readers := make([]io.Reader, 0, len(files))
+	closers := make([]io.Closer, 0, len(files))
+	for _, file := range files {
+		fh, err := os.Open(file)
+		if err != nil {
+			closeAll(closers)
+			return nil, fmt.Errorf("failed to open log file %s (%w)", file, err)
+		}
+		readers = append(readers, fh)
+		closers = append(closers, fh)
+	}
+	return &multiFileReader{Reader: io.MultiReader(readers...), closers: closers}, nil
+}
+
+type multiFileReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (m *multiFileReader) Close() error {
+	closeAll(m.closers)
+	return nil
+}
+
+func closeAll(closers []io.Closer) {
+	for _, closer := range closers {
+		_ = closer.Close()
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testLogs = `{"timestamp":"2021-03-04T05:00:00Z","level":"error","code":"LOG_WRITE_FAILED","message":"Disk full",` +
+	`"details":{"username":"foo"}}
+{"timestamp":"2021-03-04T05:30:00Z","level":"info","code":"TEST","message":"Hello",` +
+	`"details":{"username":"foo"}}
+{"timestamp":"2021-03-04T05:45:00Z","level":"error","code":"LOG_WRITE_FAILED","message":"Disk full",` +
+	`"details":{"username":"bar"}}
+{"timestamp":"2021-03-04T04:00:00Z","level":"error","code":"LOG_ROTATE_FAILED","message":"Rotate failed",` +
+	`"details":{"username":"foo"}}
+`
+
+var testNow = time.Date(2021, 3, 4, 6, 0, 0, 0, time.UTC)
+
+func TestQuery(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	assert.Equal(t, 0, run(
+		[]string{"-code", "LOG_*_FAILED", "-label", "username=foo", "-since", "1h", "-level", "warning"},
+		strings.NewReader(testLogs),
+		stdout,
+		testNow,
+	))
+	assert.Equal(
+		t,
+		`{"timestamp":"2021-03-04T05:00:00Z","level":"error","code":"LOG_WRITE_FAILED","message":"Disk full",`+
+			`"details":{"username":"foo"}}`+"\n",
+		stdout.String(),
+	)
+}
+
+func TestQueryFormat(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	assert.Equal(t, 0, run(
+		[]string{"-format", "text", "-level", "debug:info"},
+		strings.NewReader(testLogs),
+		stdout,
+		testNow,
+	))
+	assert.Equal(t, "2021-03-04T05:30:00Z\tinfo\tHello (username=foo)\n\n", stdout.String())
+}
+
+func TestQuerySummary(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	assert.Equal(t, 0, run([]string{"-summary", "code"}, strings.NewReader(testLogs), stdout, testNow))
+	assert.Equal(t, "2 LOG_WRITE_FAILED\n1 LOG_ROTATE_FAILED\n1 TEST\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run(
+		[]string{"-summary", "label:username", "-level", "error"},
+		strings.NewReader(testLogs),
+		stdout,
+		testNow,
+	))
+	assert.Equal(t, "2 foo\n1 bar\n", stdout.String())
+}
+
+func TestQueryInvalidOptions(t *testing.T) {
+	assert.Equal(t, 1, run([]string{"-summary", "user"}, strings.NewReader(testLogs), &bytes.Buffer{}, testNow))
+	assert.Equal(t, 1, run([]string{"-label", "port>x"}, strings.NewReader(testLogs), &bytes.Buffer{}, testNow))
+	assert.Equal(t, 1, run([]string{"-format", "fancy"}, strings.NewReader(testLogs), &bytes.Buffer{}, testNow))
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/containerssh/log"
+)
+
+// noLabel is the summary key for messages without the summarized label.
+const noLabel = "(none)"
+
+// summary counts the matching messages by code, level or the value of a label.
+type summary struct {
+	by     string
+	counts map[string]int
+}
+
+func newSummary(by string) (*summary, error) {
+	switch {
+	case by == "code", by == "level":
+	case strings.HasPrefix(by, "label:") && len(by) > len("label:"):
+	default:
+		return nil, fmt.Errorf("invalid summary %s, expected code, level or label:NAME", by)
+	}
+	return &summary{by: by, counts: map[string]int{}}, nil
+}
+
+func (s *summary) add(entry log.LogEntry) {
+	switch s.by {
+	case "code":
+		s.counts[entry.Message.Code()]++
+	case "level":
+		s.counts[entry.Level.String()]++
+	default:
+		value, ok := entry.Message.Labels()[log.LabelName(strings.TrimPrefix(s.by, "label:"))]
+		if !ok {
+			s.counts[noLabel]++
+			return
+		}
+		s.counts[fmt.Sprintf("%v", value)]++
+	}
+}
+
+// write prints the counts, the most frequent key first.
+func (s *summary) write(output io.Writer) error {
+	keys := make([]string, 0, len(s.counts))
+	width := 1
+	for key, count := range s.counts {
+		keys = append(keys, key)
+		if digits := len(fmt.Sprintf("%d", count)); digits > width {
+			width = digits
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if s.counts[keys[i]] != s.counts[keys[j]] {
+			return s.counts[keys[i]] > s.counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(output, "%*d %s\n", width, s.counts[key], key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
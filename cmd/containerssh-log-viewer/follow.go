@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// followReader reads a file and waits for more data at the end of the file like tail -f. If the file is rotated or
+// truncated, reading continues from the beginning of the new file once the old one has been read completely.
+type followReader struct {
+	path     string
+	file     *os.File
+	interval time.Duration
+	// done stops waiting for more data when closed, Read then returns io.EOF.
+	done <-chan struct{}
+}
+
+func newFollowReader(path string, interval time.Duration, done <-chan struct{}) (*followReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &followReader{path: path, file: file, interval: interval, done: done}, nil
+}
+
+func (f *followReader) Read(p []byte) (int, error) {
+	for {
+		n, err := f.file.Read(p)
+		if n > 0 || (err != nil && !errors.Is(err, io.EOF)) {
+			return n, err
+		}
+		reopened, err := f.reopenIfRotated()
+		if err != nil {
+			return 0, err
+		}
+		if reopened {
+			continue
+		}
+		select {
+		case <-f.done:
+			return 0, io.EOF
+		case <-time.After(f.interval):
+		}
+	}
+}
+
+// reopenIfRotated opens the file again if the path now points to a different file, and rewinds the file if it was
+// truncated.
+func (f *followReader) reopenIfRotated() (bool, error) {
+	pathStat, err := os.Stat(f.path)
+	if err != nil {
+		// The file may be missing for a short time while it is rotated.
+		return false, nil
+	}
+	fileStat, err := f.file.Stat()
+	if err != nil {
+		return false, err
+	}
+	if os.SameFile(pathStat, fileStat) {
+		offset, err := f.file.Seek(0, io.SeekCurrent)
+		if err != nil || pathStat.Size() >= offset {
+			return false, err
+		}
+		_, err = f.file.Seek(0, io.SeekStart)
+		return true, err
+	}
+	file, err := os.Open(f.path)
+	if err != nil {
+		return false, nil
+	}
+	_ = f.file.Close()
+	f.file = file
+	return true, nil
+}
+
+// Close closes the file currently being read.
+func (f *followReader) Close() error {
+	return f.file.Close()
+}
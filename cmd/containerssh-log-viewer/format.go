@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/containerssh/log"
+)
+
+// timeLayout is the layout of the time column.
+const timeLayout = "2006-01-02 15:04:05.000"
+
+// entryFormatter prints log entries in the aligned columns of the console format.
+type entryFormatter struct {
+	utc      bool
+	renderer log.ConsoleRenderer
+}
+
+func (f *entryFormatter) format(entry log.LogEntry) string {
+	timestamp := entry.Time
+	if f.utc {
+		timestamp = timestamp.UTC()
+	}
+	return f.renderer.Render(timestamp.Format(timeLayout), entry.Level.MustName(), entry.Message) + "\n"
+}
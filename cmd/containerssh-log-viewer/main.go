@@ -0,0 +1,195 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/containerssh/log"
+	"github.com/containerssh/log/codes"
+)
+
+const usage = "Usage: containerssh-log-viewer [-f] [-catalogue FILE] [-config FILE] [-color auto|always|never] " +
+	"[-utc] [-labels=false] [-descriptions=false] [FILE...]\n\n" +
+	"Pretty-prints log files written in the ljson format. If no file is given, the logs are read from the standard " +
+	"input. Multiple files, e.g. rotated logs, are read in the order given. With -f the last file is followed like " +
+	"tail -f, also across log rotations.\n\n"
+
+type options struct {
+	follow    bool
+	catalogue string
+	config    string
+	color     string
+	formatter entryFormatter
+	files     []string
+}
+
+func main() {
+	os.Exit(run(parseOptions(os.Args[1:]), os.Stdin, os.Stdout, nil))
+}
+
+func parseOptions(args []string) options {
+	flags := flag.NewFlagSet("containerssh-log-viewer", flag.ExitOnError)
+	flags.Usage = func() {
+		_, _ = fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	follow := flags.Bool("f", false, "Follow the last file and print new messages as they are written.")
+	catalogue := flags.String(
+		"catalogue",
+		"",
+		"Message code catalogue to look up code descriptions in. May be a JSON catalogue, Go file or package.",
+	)
+	config := flags.String("config", "", "YAML or JSON logger configuration describing a customized ljson format.")
+	color := flags.String("color", "auto", "Use colors: auto, always or never.")
+	utc := flags.Bool("utc", false, "Print the timestamps in UTC instead of the local time zone.")
+	labels := flags.Bool("labels", true, "Print the labels of each message.")
+	descriptions := flags.Bool("descriptions", true, "Print the description of each message code.")
+	_ = flags.Parse(args)
+	return options{
+		follow:    *follow,
+		catalogue: *catalogue,
+		config:    *config,
+		color:     *color,
+		formatter: entryFormatter{
+			utc:      *utc,
+			renderer: log.ConsoleRenderer{HideLabels: !*labels, Descriptions: *descriptions},
+		},
+		files: flags.Args(),
+	}
+}
+
+// run prints the logs and returns the exit code. When following a file, closing done stops waiting for new messages.
+func run(opts options, stdin io.Reader, stdout io.Writer, done <-chan struct{}) int {
+	config, err := setup(&opts, stdout)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	input, err := openInput(opts, stdin, done)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer func() {
+		_ = input.Close()
+	}()
+	decoder := log.NewLJSONDecoder(input, config)
+	for {
+		entry, err := decoder.Decode()
+		var lineError *log.LineDecodeError
+		switch {
+		case err == nil:
+			_, err = fmt.Fprint(stdout, opts.formatter.format(entry))
+		case errors.As(err, &lineError):
+			_, err = fmt.Fprintln(stdout, lineError.Line)
+		case errors.Is(err, io.EOF):
+			return 0
+		}
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+}
+
+// setup loads the catalogue and the logger configuration, and determines if colors should be used.
+func setup(opts *options, stdout io.Writer) (log.Config, error) {
+	config := log.Config{}
+	switch opts.color {
+	case "always":
+		opts.formatter.renderer.Color = true
+	case "never":
+	case "auto":
+		opts.formatter.renderer.Color = log.NewConsoleRenderer(stdout).Color
+	default:
+		return config, fmt.Errorf("invalid color mode: %s", opts.color)
+	}
+	if opts.catalogue != "" {
+		if err := registerCatalogue(opts.catalogue); err != nil {
+			return config, err
+		}
+	}
+	if opts.config != "" {
+		data, err := os.ReadFile(opts.config)
+		if err != nil {
+			return config, fmt.Errorf("failed to read configuration file %s (%w)", opts.config, err)
+		}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return config, fmt.Errorf("failed to parse configuration file %s (%w)", opts.config, err)
+		}
+	}
+	return config, nil
+}
+
+// registerCatalogue registers the codes of the catalogue in the runtime registry so their descriptions are found.
+func registerCatalogue(source string) error {
+	var catalogue log.MessageCodeCatalogue
+	stat, err := os.Stat(source)
+	switch {
+	case err != nil:
+		return err
+	case stat.IsDir():
+		catalogue, err = codes.GetPackageMessageCodeCatalogue(source, log.MessageCodeFilter{})
+	case strings.HasSuffix(source, ".json"):
+		var data []byte
+		if data, err = os.ReadFile(source); err == nil {
+			catalogue, err = log.ParseMessageCodeCatalogueJSON(data)
+		}
+	default:
+		catalogue, err = log.GetFileMessageCodeCatalogue(source, log.MessageCodeFilter{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load message code catalogue %s (%w)", source, err)
+	}
+	for _, info := range catalogue.Codes {
+		log.RegisterMessageCode(info)
+	}
+	return nil
+}
+
+// multiCloser closes all files opened for the input.
+type multiCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (m *multiCloser) Close() error {
+	for _, closer := range m.closers {
+		_ = closer.Close()
+	}
+	return nil
+}
+
+// openInput returns a reader reading all files in order, following the last one if requested. Without files the
+// standard input is read.
+func openInput(opts options, stdin io.Reader, done <-chan struct{}) (io.ReadCloser, error) {
+	if len(opts.files) == 0 {
+		return &multiCloser{Reader: stdin}, nil
+	}
+	input := &multiCloser{}
+	var readers []io.Reader
+	for i, file := range opts.files {
+		var reader io.ReadCloser
+		var err error
+		if opts.follow && i == len(opts.files)-1 {
+			reader, err = newFollowReader(file, 250*time.Millisecond, done)
+		} else {
+			reader, err = os.Open(file)
+		}
+		if err != nil {
+			_ = input.Close()
+			return nil, fmt.Errorf("failed to open log file %s (%w)", file, err)
+		}
+		readers = append(readers, reader)
+		input.closers = append(input.closers, reader)
+	}
+	input.Reader = io.MultiReader(readers...)
+	return input, nil
+}
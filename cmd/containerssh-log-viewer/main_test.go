@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+const (
+	testLine1 = `{"timestamp":"2021-03-04T05:06:07Z","level":"warning","code":"LOG_WRITE_FAILED",` +
+		`"message":"Disk full","details":{"file":"/var/log/containerssh.log"}}` + "\n"
+	testLine2 = `{"timestamp":"2021-03-04T05:06:08Z","level":"info","code":"TEST","message":"Hello",` +
+		`"details":{"username":"foo bar"}}` + "\n"
+)
+
+func TestViewer(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	opts := options{color: "never", formatter: entryFormatter{utc: true}}
+	assert.Equal(t, 0, run(opts, strings.NewReader(testLine1+"not json\n"+testLine2), stdout, nil))
+	assert.Equal(
+		t,
+		"2021-03-04 05:06:07.000 warning LOG_WRITE_FAILED     Disk full  file=/var/log/containerssh.log\n"+
+			"not json\n"+
+			"2021-03-04 05:06:08.000 info    TEST                 Hello  username=\"foo bar\"\n",
+		stdout.String(),
+	)
+}
+
+func TestViewerDescriptions(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	opts := options{
+		color:     "never",
+		formatter: entryFormatter{utc: true, renderer: log.ConsoleRenderer{HideLabels: true, Descriptions: true}},
+	}
+	assert.Equal(t, 0, run(opts, strings.NewReader(testLine2), stdout, nil))
+	assert.Equal(
+		t,
+		"2021-03-04 05:06:08.000 info    TEST                 Hello\n"+
+			strings.Repeat(" ", 53)+"This is message that should only be seen in unit and component "+
+			"tests, never in production.\n",
+		stdout.String(),
+	)
+}
+
+func TestViewerLongCodes(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	opts := options{
+		color:     "never",
+		formatter: entryFormatter{utc: true, renderer: log.ConsoleRenderer{HideLabels: true}},
+	}
+	line := `{"timestamp":"2021-03-04T05:06:08Z","level":"info","code":"VERY_LONG_MESSAGE_CODE","message":"Hello"}`
+	assert.Equal(t, 0, run(opts, strings.NewReader(line+"\n"+testLine2), stdout, nil))
+	assert.Equal(
+		t,
+		"2021-03-04 05:06:08.000 info    VERY_LONG_MESSAGE_CODE Hello\n"+
+			"2021-03-04 05:06:08.000 info    TEST                 Hello\n",
+		stdout.String(),
+	)
+}
+
+// syncBuffer is a buffer that can be written and read from different goroutines.
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.buf.String()
+}
+
+func TestViewerFollowRotation(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "containerssh.log")
+	assert.NoError(t, os.WriteFile(file, []byte(testLine1), 0600))
+
+	stdout := &syncBuffer{}
+	done := make(chan struct{})
+	exited := make(chan int)
+	opts := options{
+		follow:    true,
+		color:     "never",
+		formatter: entryFormatter{utc: true, renderer: log.ConsoleRenderer{HideLabels: true}},
+		files:     []string{file},
+	}
+	go func() {
+		exited <- run(opts, nil, stdout, done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return strings.Count(stdout.String(), "\n") == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, os.Rename(file, file+".1"))
+	assert.NoError(t, os.WriteFile(file, []byte(testLine2), 0600))
+	assert.Eventually(t, func() bool {
+		return strings.Count(stdout.String(), "\n") == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	close(done)
+	assert.Equal(t, 0, <-exited)
+	assert.Contains(t, stdout.String(), "TEST                 Hello\n")
+}
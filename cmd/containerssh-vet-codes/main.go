@@ -0,0 +1,15 @@
+// Command containerssh-vet-codes checks that message constructors are called with documented message code constants.
+// It can be run directly on package patterns, e.g. containerssh-vet-codes ./..., or through go vet:
+//
+//	go vet -vettool=$(which containerssh-vet-codes) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/containerssh/log/codes/analysis"
+)
+
+func main() {
+	singlechecker.Main(analysis.MessageCodeUsageAnalyzer)
+}
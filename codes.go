@@ -10,6 +10,10 @@ const ELogRotateFailed = "LOG_ROTATE_FAILED"
 // ContainerSSH failed to open the specified log file.
 const ELogFileOpenFailed = "LOG_FILE_OPEN_FAILED"
 
+// ContainerSSH dropped a label from a log message because its name or value was invalid and the label validation mode
+// is set to drop. Check the code adding the label.
+const WLogLabelDropped = "LOG_LABEL_DROPPED"
+
 // This is an untyped error. If you see this in a log that is a bug and should be reported.
 const EUnknownError = "UNKNOWN_ERROR"
 
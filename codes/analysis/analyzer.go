@@ -0,0 +1,188 @@
+// Package analysis contains a go/analysis analyzer checking that message constructors are called with documented
+// message code constants. It is kept separate from the log package so programs using the logger do not depend on the
+// analysis framework.
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/containerssh/log"
+)
+
+// logPackagePath is the import path of the log package, used to find the message constructors in the analysed code.
+const logPackagePath = "github.com/containerssh/log"
+
+// messageCodeArguments contains the index of the code argument for each message constructor.
+var messageCodeArguments = map[string]int{
+	"NewMessage":  0,
+	"UserMessage": 0,
+	"Wrap":        1,
+	"WrapUser":    1,
+}
+
+// MessageCodeUsageAnalyzer reports calls to NewMessage, UserMessage, Wrap and WrapUser whose code is not a documented
+// message code constant. Codes passed through a function parameter are not reported so wrapper functions can forward
+// them. Constants are selected in the same way as by log.GetMessageCodes, which can be changed with the -type and
+// -prefix flags.
+//
+// With the -unused flag, documented codes never passed to a constructor are reported as well. Analyzers only see a
+// package and its dependencies, so a code counts as used only if the declaring package or its tests use it.
+var MessageCodeUsageAnalyzer = newMessageCodeUsageAnalyzer()
+
+// messageCodeFact marks a constant as a documented message code so packages importing it can check its usage.
+type messageCodeFact struct {
+	Code string
+}
+
+func (*messageCodeFact) AFact() {}
+
+func (f *messageCodeFact) String() string {
+	return fmt.Sprintf("messageCode(%s)", f.Code)
+}
+
+// usageAnalyzer holds the flags of the analyzer.
+type usageAnalyzer struct {
+	typeName string
+	prefixes string
+	unused   bool
+}
+
+func newMessageCodeUsageAnalyzer() *analysis.Analyzer {
+	u := &usageAnalyzer{}
+	a := &analysis.Analyzer{
+		Name:      "messagecodes",
+		Doc:       "check that message constructors are called with documented message code constants",
+		URL:       "https://github.com/containerssh/log",
+		Run:       u.run,
+		Requires:  []*analysis.Analyzer{inspect.Analyzer},
+		FactTypes: []analysis.Fact{new(messageCodeFact)},
+	}
+	a.Flags.StringVar(&u.typeName, "type", "", "only treat constants declared with this type as message codes")
+	a.Flags.StringVar(
+		&u.prefixes,
+		"prefix",
+		"",
+		"comma-separated constant name prefixes of message codes (default \"E,M,W\")",
+	)
+	a.Flags.BoolVar(&u.unused, "unused", false, "report documented message codes the package never uses")
+	return a
+}
+
+func (u *usageAnalyzer) filter() log.MessageCodeFilter {
+	filter := log.MessageCodeFilter{TypeName: u.typeName}
+	if u.prefixes != "" {
+		filter.Prefixes = strings.Split(u.prefixes, ",")
+	}
+	return filter
+}
+
+func (u *usageAnalyzer) run(pass *analysis.Pass) (interface{}, error) {
+	var declared []log.MessageCodeDeclaration
+	for _, f := range pass.Files {
+		declared = append(declared, log.FindMessageCodeDeclarations(f, u.filter())...)
+	}
+	for _, code := range declared {
+		if obj := pass.Pkg.Scope().Lookup(code.Name); obj != nil {
+			pass.ExportObjectFact(obj, &messageCodeFact{Code: code.Info.Code})
+		}
+	}
+
+	parameters := map[types.Object]bool{}
+	used := map[types.Object]bool{}
+	ins := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	ins.Preorder([]ast.Node{(*ast.FuncType)(nil), (*ast.CallExpr)(nil)}, func(node ast.Node) {
+		switch n := node.(type) {
+		case *ast.FuncType:
+			// Function types are visited before the calls in their body.
+			for _, field := range n.Params.List {
+				for _, name := range field.Names {
+					parameters[pass.TypesInfo.Defs[name]] = true
+				}
+			}
+		case *ast.CallExpr:
+			index, ok := codeArgument(pass, n)
+			if !ok || index >= len(n.Args) {
+				return
+			}
+			if obj := checkCode(pass, parameters, n.Args[index]); obj != nil {
+				used[obj] = true
+			}
+		}
+	})
+
+	if u.unused {
+		for _, code := range declared {
+			obj := pass.Pkg.Scope().Lookup(code.Name)
+			if obj != nil && !used[obj] {
+				pass.Reportf(code.Pos, "message code %s (%s) is never used", code.Name, code.Info.Code)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// codeArgument returns the index of the code argument if the call is one of the message constructors.
+func codeArgument(pass *analysis.Pass, call *ast.CallExpr) (int, bool) {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != logPackagePath {
+		return 0, false
+	}
+	if sig, ok := fn.Type().(*types.Signature); !ok || sig.Recv() != nil {
+		return 0, false
+	}
+	index, ok := messageCodeArguments[fn.Name()]
+	return index, ok
+}
+
+// checkCode reports the code argument if it is not a documented message code constant. It returns the constant if it
+// is a documented code.
+func checkCode(pass *analysis.Pass, parameters map[types.Object]bool, expr ast.Expr) types.Object {
+	expr = ast.Unparen(expr)
+	var obj types.Object
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		pass.Reportf(e.Pos(), "message code %s is a literal instead of a documented constant", e.Value)
+		return nil
+	case *ast.Ident:
+		obj = pass.TypesInfo.Uses[e]
+	case *ast.SelectorExpr:
+		obj = pass.TypesInfo.Uses[e.Sel]
+	}
+	switch o := obj.(type) {
+	case *types.Const:
+		fact := &messageCodeFact{}
+		if !pass.ImportObjectFact(o, fact) {
+			pass.Reportf(expr.Pos(), "%s is not a documented message code", constantName(pass, o))
+			return nil
+		}
+		return o
+	case *types.Var:
+		if !parameters[o] {
+			pass.Reportf(expr.Pos(), "message code is passed in the variable %s", o.Name())
+		}
+		return nil
+	default:
+		if pass.TypesInfo.Types[expr].Value != nil {
+			pass.Reportf(expr.Pos(), "message code is a constant expression instead of a documented constant")
+		} else {
+			pass.Reportf(expr.Pos(), "message code is not a constant")
+		}
+		return nil
+	}
+}
+
+// constantName returns the name of the constant, qualified with the import path if it is from another package.
+func constantName(pass *analysis.Pass, obj *types.Const) string {
+	if obj.Pkg() == nil || obj.Pkg() == pass.Pkg {
+		return obj.Name()
+	}
+	return obj.Pkg().Path() + "." + obj.Name()
+}
@@ -0,0 +1,20 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/containerssh/log/codes/analysis"
+)
+
+func TestMessageCodeUsageAnalyzer(t *testing.T) {
+	analyzer := analysis.MessageCodeUsageAnalyzer
+	if err := analyzer.Flags.Set("unused", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = analyzer.Flags.Set("unused", "false")
+	}()
+	analysistest.Run(t, "testdata/usage", analyzer, "./...")
+}
@@ -0,0 +1,12 @@
+package usage
+
+// The backend was started.
+const MBackendStarted = "BACKEND_STARTED" // want MBackendStarted:`messageCode\(BACKEND_STARTED\)`
+
+// The backend failed.
+const EBackendFailed = "BACKEND_FAILED" // want EBackendFailed:`messageCode\(BACKEND_FAILED\)` `message code EBackendFailed \(BACKEND_FAILED\) is never used`
+
+// This code is documented but never used.
+const WNeverUsed = "NEVER_USED" // want WNeverUsed:`messageCode\(NEVER_USED\)` `message code WNeverUsed \(NEVER_USED\) is never used`
+
+const EUndocumented = "UNDOCUMENTED"
@@ -0,0 +1,7 @@
+module example.com/usage
+
+go 1.22.0
+
+require github.com/containerssh/log v0.0.0
+
+replace github.com/containerssh/log => ../../../..
@@ -0,0 +1,6 @@
+package sub
+
+// The sub backend failed.
+const ESubFailed = "SUB_FAILED" // want ESubFailed:`messageCode\(SUB_FAILED\)` `message code ESubFailed \(SUB_FAILED\) is never used`
+
+const EUndocumented = "SUB_UNDOCUMENTED"
@@ -0,0 +1,31 @@
+package usage
+
+import (
+	"errors"
+
+	logger "github.com/containerssh/log"
+
+	"example.com/usage/sub"
+)
+
+func start() {
+	_ = logger.NewMessage(MBackendStarted, "the backend was started")
+	_ = logger.UserMessage(sub.ESubFailed, "Backend failed", "the sub backend failed")
+	_ = logger.NewMessage(EUndocumented, "this code has no documentation") // want `^EUndocumented is not a documented message code$`
+	_ = logger.NewMessage(sub.EUndocumented, "this code has no documentation") // want `^example.com/usage/sub.EUndocumented is not a documented message code$`
+	_ = logger.NewMessage("LITERAL", "this code is a literal") // want `message code "LITERAL" is a literal instead of a documented constant`
+	code := EBackendFailed
+	_ = logger.Wrap(errors.New("test"), code, "this code is in a variable") // want `message code is passed in the variable code`
+	_ = logger.NewMessage(packageCode, "this code is in a package variable") // want `message code is passed in the variable packageCode`
+	_ = logger.NewMessage(logger.MTest, "codes of other modules are checked")
+	_ = logger.NewMessage(string(MBackendStarted), "this code is converted") // want `message code is a constant expression instead of a documented constant`
+	_ = logger.NewMessage(newCode(), "this code is returned by a function") // want `message code is not a constant`
+}
+
+func newCode() string {
+	return MBackendStarted
+}
+
+func wrap(err error, code string) error {
+	return logger.WrapUser(err, code, "Backend failed", "the code is forwarded")
+}
@@ -0,0 +1,4 @@
+package usage
+
+// packageCode is a package-level variable declared in a different file than the call using it.
+var packageCode = MBackendStarted
@@ -0,0 +1,80 @@
+// Package codes extracts the message codes of whole Go packages and modules, and merges the codes of several modules
+// into a single catalogue. The packages are loaded using go/packages, which is kept out of the log package so programs
+// using the logger do not depend on it.
+package codes
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/containerssh/log"
+)
+
+// GetPackageMessageCodes returns the message codes from all non-test Go files of the package in the specified
+// directory that match the current build constraints. All undocumented codes are reported in a single
+// log.MessageCodesError, the valid codes are still returned in this case.
+func GetPackageMessageCodes(dir string, filter log.MessageCodeFilter) (map[string]string, error) {
+	catalogue, err := GetPackageMessageCodeCatalogue(dir, filter)
+	return descriptions(catalogue), err
+}
+
+// GetPackageMessageCodeCatalogue is identical to GetPackageMessageCodes, but returns a catalogue including the
+// structured tags found in the documentation.
+func GetPackageMessageCodeCatalogue(dir string, filter log.MessageCodeFilter) (log.MessageCodeCatalogue, error) {
+	return loadCatalogue(dir, ".", filter)
+}
+
+// GetModuleMessageCodes returns the message codes from all packages of the Go module in the specified directory.
+// The packages are found in the same way as with go list ./..., so nested modules, vendor, testdata and hidden
+// directories are skipped.
+func GetModuleMessageCodes(root string, filter log.MessageCodeFilter) (map[string]string, error) {
+	catalogue, err := GetModuleMessageCodeCatalogue(root, filter)
+	return descriptions(catalogue), err
+}
+
+// GetModuleMessageCodeCatalogue is identical to GetModuleMessageCodes, but returns a catalogue including the
+// structured tags found in the documentation.
+func GetModuleMessageCodeCatalogue(root string, filter log.MessageCodeFilter) (log.MessageCodeCatalogue, error) {
+	return loadCatalogue(root, "./...", filter)
+}
+
+// loadCatalogue loads the packages matching the pattern in the directory using go/packages and collects the codes
+// from their non-test Go files. The go tool applies the build constraints and skips vendor, testdata and nested module
+// directories.
+func loadCatalogue(dir string, pattern string, filter log.MessageCodeFilter) (log.MessageCodeCatalogue, error) {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles,
+		Dir:  dir,
+	}, pattern)
+	if err != nil {
+		return log.MessageCodeCatalogue{}, fmt.Errorf("failed to load packages in %s (%w)", dir, err)
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].PkgPath < pkgs[j].PkgPath
+	})
+	var files []string
+	for _, pkg := range pkgs {
+		if len(pkg.GoFiles) == 0 {
+			// Directories without Go files for the current build constraints are not an error.
+			continue
+		}
+		if len(pkg.Errors) > 0 {
+			return log.MessageCodeCatalogue{}, fmt.Errorf("failed to load package %s (%w)", pkg.PkgPath, pkg.Errors[0])
+		}
+		pkgFiles := append([]string{}, pkg.GoFiles...)
+		sort.Strings(pkgFiles)
+		files = append(files, pkgFiles...)
+	}
+	return log.GetFilesMessageCodeCatalogue(files, filter)
+}
+
+// descriptions returns the descriptions of the codes keyed by code.
+func descriptions(catalogue log.MessageCodeCatalogue) map[string]string {
+	result := make(map[string]string, len(catalogue.Codes))
+	for _, info := range catalogue.Codes {
+		result[info.Code] = info.Description
+	}
+	return result
+}
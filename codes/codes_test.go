@@ -0,0 +1,50 @@
+package codes_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+	"github.com/containerssh/log/codes"
+)
+
+func TestGetPackageMessageCodes(t *testing.T) {
+	result, err := codes.GetPackageMessageCodes("testdata/module", log.MessageCodeFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"BACKEND_FAILED":   "The connection to the backend failed.",
+		"LOGIN_SUCCESSFUL": "The user logged in.",
+		"INVALID_PASSWORD": "The user provided an invalid password.",
+	}, result)
+}
+
+func TestGetPackageMessageCodesByType(t *testing.T) {
+	result, err := codes.GetPackageMessageCodes("testdata/module", log.MessageCodeFilter{TypeName: "Code"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"BACKEND_FAILED":   "The connection to the backend failed.",
+		"LOGIN_SUCCESSFUL": "The user logged in.",
+	}, result)
+}
+
+func TestGetModuleMessageCodes(t *testing.T) {
+	result, err := codes.GetModuleMessageCodes("testdata/module", log.MessageCodeFilter{Prefixes: []string{"E"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"BACKEND_FAILED":     "The connection to the backend failed.",
+		"CONFIG_READ_FAILED": "The configuration file could not be read.",
+	}, result)
+}
+
+func TestGetPackageMessageCodesReportsAllProblems(t *testing.T) {
+	result, err := codes.GetPackageMessageCodes("../testdata/codes/undocumented", log.MessageCodeFilter{})
+	// The valid codes are still returned alongside the error.
+	assert.Equal(t, map[string]string{"DOCUMENTED": "This code is documented."}, result)
+	var codesError *log.MessageCodesError
+	if !assert.True(t, errors.As(err, &codesError)) {
+		return
+	}
+	assert.Len(t, codesError.Problems, 4)
+}
@@ -0,0 +1,138 @@
+package codes
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/containerssh/log"
+)
+
+// MessageCodeSource is a package or module directory to collect message codes from when merging the codes of
+// several modules.
+type MessageCodeSource struct {
+	// Path is the directory of the package or module.
+	Path string
+	// Owner is the name of the module owning the codes. If empty, the module path from the nearest go.mod file is
+	// used.
+	Owner string
+	// Module scans all packages of the module in Path instead of a single package.
+	Module bool
+}
+
+// MergeMessageCodes collects the message codes from all sources into a single catalogue with the owner set on each
+// code. Codes defined by more than one source are reported as duplicates in a log.MessageCodesError, together with
+// any undocumented codes. The first definition of a duplicate code is kept in the catalogue returned alongside the
+// error.
+func MergeMessageCodes(sources []MessageCodeSource, filter log.MessageCodeFilter) (log.MessageCodeCatalogue, error) {
+	merged := map[string]log.MessageCodeInfo{}
+	var problems []string
+	for _, source := range sources {
+		codes, owner, err := getSourceMessageCodes(source, filter)
+		var codesError *log.MessageCodesError
+		if errors.As(err, &codesError) {
+			problems = append(problems, codesError.Problems...)
+		} else if err != nil {
+			return log.MessageCodeCatalogue{}, err
+		}
+		problems = append(problems, mergeSourceCodes(merged, codes, owner)...)
+	}
+	catalogue := log.MessageCodeCatalogue{Codes: make([]log.MessageCodeInfo, 0, len(merged))}
+	for _, info := range merged {
+		catalogue.Codes = append(catalogue.Codes, info)
+	}
+	sort.Slice(catalogue.Codes, func(i, j int) bool {
+		return catalogue.Codes[i].Code < catalogue.Codes[j].Code
+	})
+	if len(problems) > 0 {
+		return catalogue, &log.MessageCodesError{Problems: problems}
+	}
+	return catalogue, nil
+}
+
+func getSourceMessageCodes(
+	source MessageCodeSource,
+	filter log.MessageCodeFilter,
+) (log.MessageCodeCatalogue, string, error) {
+	owner := source.Owner
+	if owner == "" {
+		owner = findModulePath(source.Path)
+	}
+	var catalogue log.MessageCodeCatalogue
+	var err error
+	if source.Module {
+		catalogue, err = GetModuleMessageCodeCatalogue(source.Path, filter)
+	} else {
+		catalogue, err = GetPackageMessageCodeCatalogue(source.Path, filter)
+	}
+	return catalogue, owner, err
+}
+
+// mergeSourceCodes adds the codes of one source to the merged codes and returns the duplicates found. Duplicates keep
+// the owner that defined them first.
+func mergeSourceCodes(
+	merged map[string]log.MessageCodeInfo,
+	catalogue log.MessageCodeCatalogue,
+	owner string,
+) []string {
+	var problems []string
+	for _, info := range catalogue.Codes {
+		existing, ok := merged[info.Code]
+		if !ok {
+			info.Owner = owner
+			merged[info.Code] = info
+			continue
+		}
+		if existing.Description == info.Description {
+			problems = append(problems, fmt.Sprintf(
+				"duplicate code %s defined in %s and %s",
+				info.Code, existing.Owner, owner,
+			))
+		} else {
+			problems = append(problems, fmt.Sprintf(
+				"duplicate code %s defined in %s and %s with conflicting descriptions: %q and %q",
+				info.Code, existing.Owner, owner, existing.Description, info.Description,
+			))
+		}
+	}
+	return problems
+}
+
+// findModulePath returns the module path declared in the nearest go.mod file of the directory or its parents. If
+// there is none, the directory itself is returned.
+func findModulePath(dir string) string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	for current := absDir; ; current = filepath.Dir(current) {
+		if modulePath := readModulePath(filepath.Join(current, "go.mod")); modulePath != "" {
+			return modulePath
+		}
+		if filepath.Dir(current) == current {
+			return dir
+		}
+	}
+}
+
+func readModulePath(goModFile string) string {
+	fh, err := os.Open(goModFile)
+	if err != nil {
+		return ""
+	}
+	defer func() {
+		_ = fh.Close()
+	}()
+	scanner := bufio.NewScanner(fh)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`)
+		}
+	}
+	return ""
+}
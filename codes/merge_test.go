@@ -0,0 +1,46 @@
+package codes_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+	"github.com/containerssh/log/codes"
+)
+
+func TestMergeMessageCodes(t *testing.T) {
+	catalogue, err := codes.MergeMessageCodes([]codes.MessageCodeSource{
+		{Path: "testdata/merge/auth"},
+		{Path: "testdata/merge/backend"},
+	}, log.MessageCodeFilter{})
+	assert.Equal(t, []log.MessageCodeInfo{
+		{Code: "BACKEND_FAILED", Description: "The connection to the backend failed.", Owner: "example.com/auth"},
+		{Code: "CONFIG_READ_FAILED", Description: "The configuration file could not be read.", Owner: "example.com/auth"},
+		{Code: "CONTAINER_STARTED", Description: "The backend container was started.", Owner: "example.com/backend"},
+		{Code: "INVALID_PASSWORD", Description: "The user provided an invalid password.", Owner: "example.com/auth"},
+	}, catalogue.Codes)
+
+	var codesError *log.MessageCodesError
+	if !assert.True(t, errors.As(err, &codesError)) {
+		return
+	}
+	assert.Equal(t, []string{
+		"duplicate code BACKEND_FAILED defined in example.com/auth and example.com/backend with conflicting " +
+			"descriptions: \"The connection to the backend failed.\" and \"The backend container could not be started.\"",
+		"duplicate code CONFIG_READ_FAILED defined in example.com/auth and example.com/backend",
+	}, codesError.Problems)
+}
+
+func TestMergeMessageCodesOwner(t *testing.T) {
+	catalogue, err := codes.MergeMessageCodes([]codes.MessageCodeSource{
+		{Path: "testdata/merge/backend", Owner: "backend"},
+		{Path: "testdata/module", Module: true},
+	}, log.MessageCodeFilter{Prefixes: []string{"M"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []log.MessageCodeInfo{
+		{Code: "CONTAINER_STARTED", Description: "The backend container was started.", Owner: "backend"},
+		{Code: "LOGIN_SUCCESSFUL", Description: "The user logged in.", Owner: "github.com/containerssh/log/codes"},
+	}, catalogue.Codes)
+}
@@ -0,0 +1,10 @@
+package auth
+
+// The user provided an invalid password.
+const WInvalidPassword = "INVALID_PASSWORD"
+
+// The connection to the backend failed.
+const EBackendFailed = "BACKEND_FAILED"
+
+// The configuration file could not be read.
+const EConfigReadFailed = "CONFIG_READ_FAILED"
@@ -0,0 +1,10 @@
+package backend
+
+// The backend container could not be started.
+const EBackendFailed = "BACKEND_FAILED"
+
+// The configuration file could not be read.
+const EConfigReadFailed = "CONFIG_READ_FAILED"
+
+// The backend container was started.
+const MContainerStarted = "CONTAINER_STARTED"
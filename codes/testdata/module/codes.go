@@ -0,0 +1,19 @@
+package module
+
+// Code is the type of message codes in this package.
+type Code string
+
+// The connection to the backend failed.
+const EBackendFailed Code = "BACKEND_FAILED"
+
+const (
+	// The user logged in.
+	MLoginSuccessful Code = "LOGIN_SUCCESSFUL"
+	// The user provided an invalid password.
+	WInvalidPassword = "INVALID_PASSWORD"
+)
+
+// maxRetries is not a message code and does not need documentation to be skipped.
+const maxRetries = 5
+
+const Enabled = true
@@ -0,0 +1,3 @@
+package module
+
+const ETestOnly = "TEST_ONLY"
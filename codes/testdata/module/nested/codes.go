@@ -0,0 +1,4 @@
+package nested
+
+// This code belongs to a different module.
+const ENestedModule = "NESTED_MODULE"
@@ -0,0 +1,4 @@
+package sub
+
+// The configuration file could not be read.
+const EConfigReadFailed = "CONFIG_READ_FAILED"
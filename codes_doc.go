@@ -1,3 +1,5 @@
 package log
 
-//go:generate go run cmd/containerssh-generate-codes/main.go
+// The generator is a separate module so the log package does not depend on go/packages.
+//go:generate go run -C cmd ./containerssh-generate-codes ../codes.go ../CODES.md
+//go:generate go run -C cmd ./containerssh-generate-codes -format go ../codes.go ../codes_registry.go
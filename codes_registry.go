@@ -0,0 +1,30 @@
+// Code generated by containerssh-generate-codes. DO NOT EDIT.
+
+package log
+
+func init() {
+	RegisterMessageCode(MessageCodeInfo{
+		Code:        "LOG_FILE_OPEN_FAILED",
+		Description: "ContainerSSH failed to open the specified log file.",
+	})
+	RegisterMessageCode(MessageCodeInfo{
+		Code:        "LOG_LABEL_DROPPED",
+		Description: "ContainerSSH dropped a label from a log message because its name or value was invalid and the label validation mode is set to drop. Check the code adding the label.",
+	})
+	RegisterMessageCode(MessageCodeInfo{
+		Code:        "LOG_ROTATE_FAILED",
+		Description: "ContainerSSH cannot rotate the logs as requested because of an underlying error.",
+	})
+	RegisterMessageCode(MessageCodeInfo{
+		Code:        "LOG_WRITE_FAILED",
+		Description: "ContainerSSH cannot write to the specified log file. This usually happens because the underlying filesystem is full or the log is located on a non-local storage (e.g. NFS), which is not supported.",
+	})
+	RegisterMessageCode(MessageCodeInfo{
+		Code:        "TEST",
+		Description: "This is message that should only be seen in unit and component tests, never in production.",
+	})
+	RegisterMessageCode(MessageCodeInfo{
+		Code:        "UNKNOWN_ERROR",
+		Description: "This is an untyped error. If you see this in a log that is a bug and should be reported.",
+	})
+}
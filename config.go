@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
 // Config describes the logging settings.
@@ -18,6 +20,19 @@ type Config struct {
 	// Format describes the log message format
 	Format Format `json:"format" yaml:"format" default:"ljson"`
 
+	// Template is the Go text/template used to render each line if Format is set to "template".
+	Template string `json:"template" yaml:"template"`
+
+	// Timestamp configures the format of the timestamps in log messages.
+	Timestamp TimestampConfig `json:"timestamp" yaml:"timestamp"`
+
+	// LJSON configures the field names and contents of the ljson format.
+	LJSON LJSONConfig `json:"ljson" yaml:"ljson"`
+
+	// LabelValidation describes how the logger handles invalid label names and values, both of the labels passed to
+	// WithLabel and of the labels of the messages it writes. If empty, labels are not validated.
+	LabelValidation LabelValidation `json:"labelValidation" yaml:"labelValidation"`
+
 	// Destination is the target to write the log messages to.
 	Destination Destination `json:"destination" yaml:"destination" default:"stdout"`
 
@@ -27,11 +42,33 @@ type Config struct {
 	// Syslog configures the syslog destination.
 	Syslog SyslogConfig `json:"syslog" yaml:"syslog"`
 
+	// OTLP configures the OpenTelemetry log export destination.
+	OTLP OTLPConfig `json:"otlp" yaml:"otlp"`
+
+	// FluentForward configures the Fluentd / Fluent Bit forward protocol destination.
+	FluentForward FluentForwardConfig `json:"fluentforward" yaml:"fluentforward"`
+
+	// SplunkHEC configures the Splunk HTTP Event Collector destination.
+	SplunkHEC SplunkHECConfig `json:"splunkhec" yaml:"splunkhec"`
+
+	// Webhook configures the HTTP webhook destination.
+	Webhook WebhookConfig `json:"webhook" yaml:"webhook"`
+
+	// GCP configures the Google Cloud Logging format.
+	GCP GCPConfig `json:"gcp" yaml:"gcp"`
+
+	// Device describes the product in the header of the CEF and LEEF formats.
+	Device DeviceConfig `json:"device" yaml:"device"`
+
 	// T is the Go test for logging purposes.
 	T *testing.T `json:"-" yaml:"-"`
 
 	// Stdout is the standard output used by the DestinationStdout destination.
 	Stdout io.Writer `json:"-" yaml:"-"`
+
+	// Clock returns the current time for timestamping log messages. Defaults to time.Now. Useful for deterministic
+	// tests.
+	Clock func() time.Time `json:"-" yaml:"-"`
 }
 
 // Validate validates the log configuration.
@@ -42,12 +79,54 @@ func (c *Config) Validate() error {
 	if err := c.Format.Validate(); err != nil {
 		return err
 	}
+	if c.Format == FormatTemplate {
+		if _, err := parseLineTemplate(c.Template); err != nil {
+			return err
+		}
+	}
+	if err := c.Timestamp.Validate(); err != nil {
+		return err
+	}
+	if c.Format == FormatLJSON {
+		if err := c.LJSON.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := c.LabelValidation.Validate(); err != nil {
+		return err
+	}
 	if err := c.Destination.Validate(); err != nil {
 		return err
 	}
+	if c.Format.isBinary() && c.Destination != DestinationFile && c.Destination != DestinationStdout {
+		return fmt.Errorf("the %s log format is only supported by the file and stdout destinations", c.Format)
+	}
+	if c.Format == FormatConsole && c.Destination == DestinationSyslog {
+		return fmt.Errorf("the %s log format is not supported by the syslog destination", c.Format)
+	}
 	if c.Destination == DestinationTest && c.T == nil {
 		return fmt.Errorf("test log destination selected but no test case provided")
 	}
+	if c.Destination == DestinationOTLP {
+		if err := c.OTLP.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Destination == DestinationFluentForward {
+		if err := c.FluentForward.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Destination == DestinationSplunkHEC {
+		if err := c.SplunkHEC.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Destination == DestinationWebhook {
+		if err := c.Webhook.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -225,6 +304,22 @@ const (
 	FormatLJSON Format = "ljson"
 	// FormatText prints the logs as plain text.
 	FormatText Format = "text"
+	// FormatECS is a newline-delimited JSON log format conforming to the Elastic Common Schema.
+	FormatECS Format = "ecs"
+	// FormatGCP is a newline-delimited JSON log format recognized by Google Cloud Logging.
+	FormatGCP Format = "gcp"
+	// FormatCEF prints the logs in the ArcSight Common Event Format.
+	FormatCEF Format = "cef"
+	// FormatLEEF prints the logs in the IBM Log Event Extended Format.
+	FormatLEEF Format = "leef"
+	// FormatTemplate prints the logs using the Go template provided in Config.Template.
+	FormatTemplate Format = "template"
+	// FormatConsole prints the logs in aligned, colored columns for interactive use.
+	FormatConsole Format = "console"
+	// FormatCBOR writes length-prefixed CBOR records. Only supported by the file and stdout destinations.
+	FormatCBOR Format = "cbor"
+	// FormatMsgpack writes length-prefixed MessagePack records. Only supported by the file and stdout destinations.
+	FormatMsgpack Format = "msgpack"
 )
 
 // Validate returns an error if the format is invalid.
@@ -232,6 +327,14 @@ func (format Format) Validate() error {
 	switch format {
 	case FormatLJSON:
 	case FormatText:
+	case FormatECS:
+	case FormatGCP:
+	case FormatCEF:
+	case FormatLEEF:
+	case FormatTemplate:
+	case FormatConsole:
+	case FormatCBOR:
+	case FormatMsgpack:
 	default:
 		return fmt.Errorf("invalid log format: %s", format)
 	}
@@ -240,6 +343,256 @@ func (format Format) Validate() error {
 
 // endregion
 
+// region GCP
+
+// GCPConfig configures the Google Cloud Logging structured JSON format.
+type GCPConfig struct {
+	// ProjectID is the Google Cloud project ID used to build the full trace resource name. If empty, the bare trace ID
+	// is logged.
+	ProjectID string `json:"projectId" yaml:"projectId"`
+	// TraceIDLabel is the label holding the hex-encoded trace ID of a message.
+	TraceIDLabel LabelName `json:"traceIdLabel" yaml:"traceIdLabel" default:"traceId"`
+	// SpanIDLabel is the label holding the hex-encoded span ID of a message.
+	SpanIDLabel LabelName `json:"spanIdLabel" yaml:"spanIdLabel" default:"spanId"`
+}
+
+// endregion
+
+// region Device
+
+// DeviceConfig describes the product sending the log messages in the CEF and LEEF formats.
+type DeviceConfig struct {
+	// Vendor is the name of the vendor of the product.
+	Vendor string `json:"vendor" yaml:"vendor" default:"ContainerSSH"`
+	// Product is the name of the product.
+	Product string `json:"product" yaml:"product" default:"ContainerSSH"`
+	// Version is the version of the product.
+	Version string `json:"version" yaml:"version"`
+}
+
+// endregion
+
+// region LJSON
+
+// LJSONLabelMode describes where the labels are placed in the ljson format.
+//swagger:enum
+type LJSONLabelMode string
+
+const (
+	// LJSONLabelModeNested places the labels in a separate object under the details field.
+	LJSONLabelModeNested LJSONLabelMode = "nested"
+	// LJSONLabelModeFlat places the labels on the top level of the JSON object. Labels colliding with other fields are
+	// prefixed with LJSONConfig.CollisionPrefix.
+	LJSONLabelModeFlat LJSONLabelMode = "flat"
+)
+
+// Validate returns an error if the label mode is invalid.
+func (m LJSONLabelMode) Validate() error {
+	switch m {
+	case "":
+	case LJSONLabelModeNested:
+	case LJSONLabelModeFlat:
+	default:
+		return fmt.Errorf("invalid ljson label mode: %s", m)
+	}
+	return nil
+}
+
+// LJSONConfig configures the ljson format.
+type LJSONConfig struct {
+	// Fields changes the names of the fields in the JSON object.
+	Fields LJSONFieldsConfig `json:"fields" yaml:"fields"`
+	// Labels describes if the labels are nested under the details field or placed on the top level.
+	Labels LJSONLabelMode `json:"labels" yaml:"labels" default:"nested"`
+	// CollisionPrefix is prepended to the names of flattened labels that collide with other fields. The decoder keeps
+	// the prefix since it cannot tell such labels apart from labels that already had the prefixed name.
+	CollisionPrefix string `json:"collisionPrefix" yaml:"collisionPrefix" default:"label_"`
+	// UserMessage adds the message intended for the user.
+	UserMessage bool `json:"userMessage" yaml:"userMessage" default:"false"`
+	// Hostname adds the host name of the machine.
+	Hostname bool `json:"hostname" yaml:"hostname" default:"false"`
+	// PID adds the process ID.
+	PID bool `json:"pid" yaml:"pid" default:"false"`
+	// Extra contains static fields added to every message.
+	Extra map[string]string `json:"extra" yaml:"extra"`
+}
+
+// Validate validates the ljson configuration.
+func (c LJSONConfig) Validate() error {
+	if err := c.Labels.Validate(); err != nil {
+		return err
+	}
+	fields := c.Fields.withDefaults()
+	used := map[string]string{}
+	for _, field := range []struct {
+		option  string
+		name    string
+		enabled bool
+	}{
+		{"timestamp", fields.Timestamp, true},
+		{"level", fields.Level, true},
+		{"code", fields.Code, true},
+		{"message", fields.Message, true},
+		{"userMessage", fields.UserMessage, c.UserMessage},
+		{"hostname", fields.Hostname, c.Hostname},
+		{"pid", fields.PID, c.PID},
+		{"details", fields.Details, c.Labels != LJSONLabelModeFlat},
+	} {
+		if !field.enabled {
+			continue
+		}
+		if other, ok := used[field.name]; ok {
+			return fmt.Errorf("the ljson %s and %s fields have the same name: %s", other, field.option, field.name)
+		}
+		used[field.name] = field.option
+	}
+	for _, name := range sortedKeys(c.Extra) {
+		if option, ok := used[name]; ok {
+			return fmt.Errorf("the ljson extra field %s collides with the %s field", name, option)
+		}
+	}
+	return nil
+}
+
+// LJSONFieldsConfig contains the names of the fields in the ljson format. Empty names are replaced with the
+// defaults.
+type LJSONFieldsConfig struct {
+	// Timestamp is the name of the timestamp field.
+	Timestamp string `json:"timestamp" yaml:"timestamp" default:"timestamp"`
+	// Level is the name of the level field.
+	Level string `json:"level" yaml:"level" default:"level"`
+	// Code is the name of the message code field.
+	Code string `json:"code" yaml:"code" default:"code"`
+	// Message is the name of the field containing the explanation.
+	Message string `json:"message" yaml:"message" default:"message"`
+	// Details is the name of the field containing the labels if they are nested.
+	Details string `json:"details" yaml:"details" default:"details"`
+	// UserMessage is the name of the field containing the user message.
+	UserMessage string `json:"userMessage" yaml:"userMessage" default:"userMessage"`
+	// Hostname is the name of the host name field.
+	Hostname string `json:"hostname" yaml:"hostname" default:"hostname"`
+	// PID is the name of the process ID field.
+	PID string `json:"pid" yaml:"pid" default:"pid"`
+}
+
+func (c LJSONFieldsConfig) withDefaults() LJSONFieldsConfig {
+	defaults := []struct {
+		field        *string
+		defaultValue string
+	}{
+		{&c.Timestamp, "timestamp"},
+		{&c.Level, "level"},
+		{&c.Code, "code"},
+		{&c.Message, "message"},
+		{&c.Details, "details"},
+		{&c.UserMessage, "userMessage"},
+		{&c.Hostname, "hostname"},
+		{&c.PID, "pid"},
+	}
+	for _, d := range defaults {
+		if *d.field == "" {
+			*d.field = d.defaultValue
+		}
+	}
+	return c
+}
+
+// endregion
+
+// region Timestamp
+
+// TimestampFormat is the format of the timestamps in log messages.
+//swagger:enum
+type TimestampFormat string
+
+const (
+	// TimestampFormatRFC3339 formats timestamps according to RFC 3339 with second precision.
+	TimestampFormatRFC3339 TimestampFormat = "rfc3339"
+	// TimestampFormatRFC3339Nano formats timestamps according to RFC 3339 with nanosecond precision.
+	TimestampFormatRFC3339Nano TimestampFormat = "rfc3339nano"
+	// TimestampFormatUnix formats timestamps as seconds since the Unix epoch.
+	TimestampFormatUnix TimestampFormat = "unix"
+	// TimestampFormatUnixMilli formats timestamps as milliseconds since the Unix epoch.
+	TimestampFormatUnixMilli TimestampFormat = "unixmilli"
+	// TimestampFormatUnixNano formats timestamps as nanoseconds since the Unix epoch.
+	TimestampFormatUnixNano TimestampFormat = "unixnano"
+	// TimestampFormatCustom formats timestamps using the Go time layout in TimestampConfig.Layout.
+	TimestampFormatCustom TimestampFormat = "custom"
+)
+
+// Validate returns an error if the timestamp format is invalid.
+func (f TimestampFormat) Validate() error {
+	switch f {
+	case "":
+	case TimestampFormatRFC3339:
+	case TimestampFormatRFC3339Nano:
+	case TimestampFormatUnix:
+	case TimestampFormatUnixMilli:
+	case TimestampFormatUnixNano:
+	case TimestampFormatCustom:
+	default:
+		return fmt.Errorf("invalid timestamp format: %s", f)
+	}
+	return nil
+}
+
+// TimestampConfig configures the timestamps in log messages.
+type TimestampConfig struct {
+	// Format is the timestamp format used by the text, ljson and console formats.
+	Format TimestampFormat `json:"format" yaml:"format" default:"rfc3339"`
+	// Layout is the Go time layout used if Format is set to "custom".
+	Layout string `json:"layout" yaml:"layout"`
+	// UTC converts all timestamps to UTC instead of the local time zone.
+	UTC bool `json:"utc" yaml:"utc" default:"false"`
+}
+
+// Validate validates the timestamp configuration.
+func (c TimestampConfig) Validate() error {
+	if err := c.Format.Validate(); err != nil {
+		return err
+	}
+	if c.Format == TimestampFormatCustom && c.Layout == "" {
+		return fmt.Errorf("custom timestamp format selected but no layout provided")
+	}
+	return nil
+}
+
+// endregion
+
+// region LabelValidation
+
+// LabelValidation describes how invalid label names and values are handled.
+//swagger:enum
+type LabelValidation string
+
+const (
+	// LabelValidationNone accepts all label names and values.
+	LabelValidationNone LabelValidation = "none"
+	// LabelValidationPanic panics if a label name or value is invalid.
+	LabelValidationPanic LabelValidation = "panic"
+	// LabelValidationCoerce replaces invalid characters in label names with underscores and converts invalid label
+	// values to strings.
+	LabelValidationCoerce LabelValidation = "coerce"
+	// LabelValidationDrop drops invalid labels and logs a warning with the LOG_LABEL_DROPPED code.
+	LabelValidationDrop LabelValidation = "drop"
+)
+
+// Validate returns an error if the label validation mode is invalid.
+func (v LabelValidation) Validate() error {
+	switch v {
+	case "":
+	case LabelValidationNone:
+	case LabelValidationPanic:
+	case LabelValidationCoerce:
+	case LabelValidationDrop:
+	default:
+		return fmt.Errorf("invalid label validation mode: %s", v)
+	}
+	return nil
+}
+
+// endregion
+
 // region Destination
 
 // Destination is the output to write to.
@@ -255,6 +608,14 @@ const (
 	DestinationSyslog Destination = "syslog"
 	// DestinationTest writes the logs to the *testing.T facility.
 	DestinationTest Destination = "test"
+	// DestinationOTLP exports the log messages to an OpenTelemetry collector using OTLP/HTTP.
+	DestinationOTLP Destination = "otlp"
+	// DestinationFluentForward sends the log messages to Fluentd or Fluent Bit using the forward protocol.
+	DestinationFluentForward Destination = "fluentforward"
+	// DestinationSplunkHEC sends the log messages to a Splunk HTTP Event Collector.
+	DestinationSplunkHEC Destination = "splunkhec"
+	// DestinationWebhook sends the log messages to an HTTP endpoint using a user-defined payload template.
+	DestinationWebhook Destination = "webhook"
 )
 
 // Validate validates the output target.
@@ -264,6 +625,10 @@ func (o Destination) Validate() error {
 	case DestinationFile:
 	case DestinationSyslog:
 	case DestinationTest:
+	case DestinationOTLP:
+	case DestinationFluentForward:
+	case DestinationSplunkHEC:
+	case DestinationWebhook:
 	default:
 		return fmt.Errorf("invalid destination: %s", o)
 	}
@@ -542,3 +907,321 @@ func (c *SyslogConfig) Validate() error {
 }
 
 // endregion
+
+// region Batch
+
+// BatchConfig configures how log messages are grouped before they are sent to a remote destination.
+type BatchConfig struct {
+	// Size is the maximum number of messages sent in a single batch.
+	Size int `json:"size" yaml:"size" default:"100"`
+	// Interval is the maximum time a message waits in the batch before it is sent.
+	Interval time.Duration `json:"interval" yaml:"interval" default:"5s"`
+	// QueueSize is the maximum number of batches waiting to be sent. Batches are dropped when the queue is full.
+	QueueSize int `json:"queueSize" yaml:"queueSize" default:"10"`
+}
+
+// Validate validates the batch configuration.
+func (c BatchConfig) Validate() error {
+	if c.Size < 1 {
+		return fmt.Errorf("invalid batch size: %d", c.Size)
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("invalid batch interval: %s", c.Interval)
+	}
+	if c.QueueSize < 1 {
+		return fmt.Errorf("invalid batch queue size: %d", c.QueueSize)
+	}
+	return nil
+}
+
+// endregion
+
+// region Retry
+
+// RetryConfig configures how failed requests to a remote destination are retried.
+type RetryConfig struct {
+	// Attempts is the maximum number of attempts for a single request, including the first one.
+	Attempts int `json:"attempts" yaml:"attempts" default:"5"`
+	// Backoff is the wait time before the first retry. It is doubled after every failed attempt.
+	Backoff time.Duration `json:"backoff" yaml:"backoff" default:"500ms"`
+	// MaxBackoff is the maximum wait time between two attempts.
+	MaxBackoff time.Duration `json:"maxBackoff" yaml:"maxBackoff" default:"30s"`
+}
+
+// Validate validates the retry configuration.
+func (c RetryConfig) Validate() error {
+	if c.Attempts < 1 {
+		return fmt.Errorf("invalid number of retry attempts: %d", c.Attempts)
+	}
+	if c.Backoff < 0 {
+		return fmt.Errorf("invalid retry backoff: %s", c.Backoff)
+	}
+	if c.MaxBackoff < c.Backoff {
+		return fmt.Errorf("invalid maximum retry backoff: %s", c.MaxBackoff)
+	}
+	return nil
+}
+
+// endregion
+
+// region OTLP
+
+// OTLPEncoding is the payload encoding used for OTLP/HTTP requests.
+//swagger:enum
+type OTLPEncoding string
+
+const (
+	// OTLPEncodingProtobuf sends binary protobuf payloads.
+	OTLPEncodingProtobuf OTLPEncoding = "protobuf"
+	// OTLPEncodingJSON sends JSON-encoded protobuf payloads.
+	OTLPEncodingJSON OTLPEncoding = "json"
+)
+
+// Validate returns an error if the encoding is invalid.
+func (e OTLPEncoding) Validate() error {
+	switch e {
+	case OTLPEncodingProtobuf:
+	case OTLPEncodingJSON:
+	default:
+		return fmt.Errorf("invalid OTLP encoding: %s", e)
+	}
+	return nil
+}
+
+// OTLPConfig is the configuration for exporting logs over OTLP/HTTP.
+type OTLPConfig struct {
+	// URL is the full URL of the OTLP/HTTP logs endpoint.
+	URL string `json:"url" yaml:"url" default:"http://localhost:4318/v1/logs"`
+	// Encoding is the payload encoding, either protobuf or json.
+	Encoding OTLPEncoding `json:"encoding" yaml:"encoding" default:"protobuf"`
+	// Headers are additional HTTP headers sent with every request, for example for authentication.
+	Headers map[string]string `json:"headers" yaml:"headers"`
+	// Timeout is the timeout for a single export request.
+	Timeout time.Duration `json:"timeout" yaml:"timeout" default:"10s"`
+	// Batch configures how many messages are sent in one request.
+	Batch BatchConfig `json:"batch" yaml:"batch"`
+	// Resource describes the entity producing the logs.
+	Resource OTLPResourceConfig `json:"resource" yaml:"resource"`
+	// TraceIDLabel is the label holding the hex-encoded trace ID of a message.
+	TraceIDLabel LabelName `json:"traceIdLabel" yaml:"traceIdLabel" default:"traceId"`
+	// SpanIDLabel is the label holding the hex-encoded span ID of a message.
+	SpanIDLabel LabelName `json:"spanIdLabel" yaml:"spanIdLabel" default:"spanId"`
+}
+
+// Validate validates the OTLP configuration.
+func (c *OTLPConfig) Validate() error {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid OTLP URL: %s (%w)", c.URL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid OTLP URL scheme: %s", u.Scheme)
+	}
+	if err := c.Encoding.Validate(); err != nil {
+		return err
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("invalid OTLP timeout: %s", c.Timeout)
+	}
+	if err := c.Batch.Validate(); err != nil {
+		return err
+	}
+	if c.Resource.ServiceName == "" {
+		return fmt.Errorf("no OTLP service name provided")
+	}
+	return nil
+}
+
+// OTLPResourceConfig describes the resource block attached to exported logs.
+type OTLPResourceConfig struct {
+	// ServiceName is sent as the service.name resource attribute.
+	ServiceName string `json:"serviceName" yaml:"serviceName" default:"containerssh"`
+	// HostName is sent as the host.name resource attribute. Defaults to the system host name.
+	HostName string `json:"hostName" yaml:"hostName"`
+	// Attributes are additional resource attributes.
+	Attributes map[string]string `json:"attributes" yaml:"attributes"`
+}
+
+// endregion
+
+// region Fluent Forward
+
+// FluentForwardMode is the event mode of the Fluentd forward protocol.
+//swagger:enum
+type FluentForwardMode string
+
+const (
+	// FluentForwardModeMessage sends each log message in a separate request.
+	FluentForwardModeMessage FluentForwardMode = "message"
+	// FluentForwardModeForward sends batches of log messages as an array of entries.
+	FluentForwardModeForward FluentForwardMode = "forward"
+	// FluentForwardModePackedForward sends batches of log messages as a single binary blob of entries.
+	FluentForwardModePackedForward FluentForwardMode = "packedforward"
+)
+
+// Validate returns an error if the mode is invalid.
+func (m FluentForwardMode) Validate() error {
+	switch m {
+	case FluentForwardModeMessage:
+	case FluentForwardModeForward:
+	case FluentForwardModePackedForward:
+	default:
+		return fmt.Errorf("invalid fluent forward mode: %s", m)
+	}
+	return nil
+}
+
+// FluentForwardConfig is the configuration for the Fluentd forward protocol destination.
+type FluentForwardConfig struct {
+	// Address is the host:port of the forward input. Addresses starting with / are treated as UNIX sockets.
+	Address string `json:"address" yaml:"address" default:"localhost:24224"`
+	// Tag is the Fluentd tag to send the log messages with.
+	Tag string `json:"tag" yaml:"tag" default:"containerssh"`
+	// Mode is the forward protocol event mode. The forward and packedforward modes send messages in batches.
+	Mode FluentForwardMode `json:"mode" yaml:"mode" default:"message"`
+	// RequireAck requests an acknowledgement from the server for each request.
+	RequireAck bool `json:"requireAck" yaml:"requireAck" default:"false"`
+	// Timeout is the timeout for connecting, writing and waiting for acknowledgements.
+	Timeout time.Duration `json:"timeout" yaml:"timeout" default:"10s"`
+	// Batch configures the batching in the forward and packedforward modes.
+	Batch BatchConfig `json:"batch" yaml:"batch"`
+}
+
+// Validate validates the fluent forward configuration.
+func (c *FluentForwardConfig) Validate() error {
+	if c.Address == "" {
+		return fmt.Errorf("no fluent forward address provided")
+	}
+	if c.Tag == "" {
+		return fmt.Errorf("no fluent forward tag provided")
+	}
+	if err := c.Mode.Validate(); err != nil {
+		return err
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("invalid fluent forward timeout: %s", c.Timeout)
+	}
+	if c.Mode != FluentForwardModeMessage {
+		if err := c.Batch.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// endregion
+
+// region Splunk HEC
+
+// SplunkHECConfig is the configuration for the Splunk HTTP Event Collector destination.
+type SplunkHECConfig struct {
+	// URL is the base URL of the HTTP Event Collector. The /services/collector/event path is appended.
+	URL string `json:"url" yaml:"url" default:"https://localhost:8088"`
+	// Token is the HEC token used for authentication.
+	Token string `json:"token" yaml:"token"`
+	// Index is the Splunk index to send events to. Uses the default index of the token if empty.
+	Index string `json:"index" yaml:"index"`
+	// SourceType is the sourcetype of the sent events.
+	SourceType string `json:"sourcetype" yaml:"sourcetype" default:"containerssh"`
+	// Source is the source of the sent events. Uses the default source of the token if empty.
+	Source string `json:"source" yaml:"source"`
+	// Host is the host field of the sent events. Defaults to the system host name.
+	Host string `json:"host" yaml:"host"`
+	// Timeout is the timeout for a single HTTP request.
+	Timeout time.Duration `json:"timeout" yaml:"timeout" default:"10s"`
+	// Batch configures how many events are sent in one request.
+	Batch BatchConfig `json:"batch" yaml:"batch"`
+	// Retry configures retrying requests the server rejected with 503 Service Unavailable.
+	Retry RetryConfig `json:"retry" yaml:"retry"`
+	// Ack enables waiting for indexer acknowledgement of each batch.
+	Ack bool `json:"ack" yaml:"ack" default:"false"`
+	// Channel is the channel ID used for indexer acknowledgement. A random channel ID is generated if empty.
+	Channel string `json:"channel" yaml:"channel"`
+	// AckInterval is the time between two acknowledgement status requests.
+	AckInterval time.Duration `json:"ackInterval" yaml:"ackInterval" default:"1s"`
+	// AckTimeout is the maximum time to wait for the acknowledgement of a batch.
+	AckTimeout time.Duration `json:"ackTimeout" yaml:"ackTimeout" default:"60s"`
+}
+
+// Validate validates the Splunk HEC configuration.
+func (c *SplunkHECConfig) Validate() error {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid Splunk HEC URL: %s (%w)", c.URL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid Splunk HEC URL scheme: %s", u.Scheme)
+	}
+	if c.Token == "" {
+		return fmt.Errorf("no Splunk HEC token provided")
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("invalid Splunk HEC timeout: %s", c.Timeout)
+	}
+	if err := c.Batch.Validate(); err != nil {
+		return err
+	}
+	if err := c.Retry.Validate(); err != nil {
+		return err
+	}
+	if c.Ack && (c.AckInterval <= 0 || c.AckTimeout <= 0) {
+		return fmt.Errorf("invalid Splunk HEC acknowledgement interval or timeout")
+	}
+	return nil
+}
+
+// endregion
+
+// region Webhook
+
+// WebhookConfig is the configuration for the HTTP webhook destination.
+type WebhookConfig struct {
+	// URL is the URL of the webhook receiver.
+	URL string `json:"url" yaml:"url"`
+	// Method is the HTTP method used for sending the requests.
+	Method string `json:"method" yaml:"method" default:"POST"`
+	// Headers are additional HTTP headers sent with every request.
+	Headers map[string]string `json:"headers" yaml:"headers"`
+	// ContentType is the content type of the rendered payload.
+	ContentType string `json:"contentType" yaml:"contentType" default:"application/json"`
+	// Template is the Go text/template used to render the request body. The top level fields refer to the first
+	// message of the batch, while .Messages contains all messages in the batch.
+	Template string `json:"template" yaml:"template" default:"{\"level\":{{ json .Level }},\"code\":{{ json .Code }},\"message\":{{ json .Explanation }},\"labels\":{{ json .Labels }}}"`
+	// Level is the minimum level of the messages sent to the webhook.
+	Level Level `json:"level" yaml:"level" default:"2"`
+	// Timeout is the timeout for a single HTTP request.
+	Timeout time.Duration `json:"timeout" yaml:"timeout" default:"10s"`
+	// Batch configures how many messages are rendered into one request. Each message is sent separately by default.
+	Batch BatchConfig `json:"batch" yaml:"batch" default:"{\"size\":1,\"interval\":5000000000}"`
+	// Retry configures retrying requests that failed with a transport error, 429 or a 5xx status code.
+	Retry RetryConfig `json:"retry" yaml:"retry"`
+}
+
+// Validate validates the webhook configuration.
+func (c *WebhookConfig) Validate() error {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %s (%w)", c.URL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid webhook URL scheme: %s", u.Scheme)
+	}
+	if c.Method == "" {
+		return fmt.Errorf("no webhook method provided")
+	}
+	if _, err := parseWebhookTemplate(c.Template); err != nil {
+		return err
+	}
+	if err := c.Level.Validate(); err != nil {
+		return err
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("invalid webhook timeout: %s", c.Timeout)
+	}
+	if err := c.Batch.Validate(); err != nil {
+		return err
+	}
+	return c.Retry.Validate()
+}
+
+// endregion
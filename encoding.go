@@ -0,0 +1,85 @@
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// primitiveEncoder is implemented by the binary encoders in this package so label values of arbitrary types can be
+// written using the same rules.
+type primitiveEncoder interface {
+	writeNil()
+	writeBool(v bool)
+	writeInt(v int64)
+	writeUint(v uint64)
+	writeFloat(v float64)
+	writeString(v string)
+	writeBinary(v []byte)
+	writeArrayHeader(l int)
+	writeMapHeader(l int)
+}
+
+// writeEncodedValue writes an arbitrary value. Types without a native equivalent are written as strings, maps are
+// written with string keys in sorted order.
+func writeEncodedValue(e primitiveEncoder, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		e.writeNil()
+	case string:
+		e.writeString(v)
+	case bool:
+		e.writeBool(v)
+	case []byte:
+		e.writeBinary(v)
+	case time.Time:
+		e.writeString(v.Format(time.RFC3339Nano))
+	case error:
+		e.writeString(v.Error())
+	case fmt.Stringer:
+		e.writeString(v.String())
+	default:
+		writeEncodedReflect(e, reflect.ValueOf(value))
+	}
+}
+
+func writeEncodedReflect(e primitiveEncoder, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.writeInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		e.writeFloat(v.Float())
+	case reflect.String:
+		e.writeString(v.String())
+	case reflect.Bool:
+		e.writeBool(v.Bool())
+	case reflect.Slice, reflect.Array:
+		e.writeArrayHeader(v.Len())
+		for i := 0; i < v.Len(); i++ {
+			writeEncodedValue(e, v.Index(i).Interface())
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprintf("%v", keys[i].Interface()) < fmt.Sprintf("%v", keys[j].Interface())
+		})
+		e.writeMapHeader(len(keys))
+		for _, key := range keys {
+			e.writeString(fmt.Sprintf("%v", key.Interface()))
+			writeEncodedValue(e, v.MapIndex(key).Interface())
+		}
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			e.writeNil()
+		} else {
+			writeEncodedValue(e, v.Elem().Interface())
+		}
+	case reflect.Invalid:
+		e.writeNil()
+	default:
+		e.writeString(fmt.Sprintf("%v", v.Interface()))
+	}
+}
@@ -0,0 +1,10 @@
+package log
+
+import (
+	"io"
+)
+
+// DecodeMsgpack exposes the internal MessagePack decoder for tests.
+func DecodeMsgpack(r io.Reader) (interface{}, error) {
+	return newMsgpackDecoder(r).decode()
+}
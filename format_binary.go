@@ -0,0 +1,175 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// binaryRecordEncoder is implemented by the encoders used for the binary formats.
+type binaryRecordEncoder interface {
+	primitiveEncoder
+	writeValue(value interface{})
+	writeTimestamp(t time.Time)
+	bytes() []byte
+}
+
+// isBinary returns true if the format writes length-prefixed binary records instead of lines.
+func (format Format) isBinary() bool {
+	return format == FormatCBOR || format == FormatMsgpack
+}
+
+// createRecordBinary encodes a message with the same fields as the ljson format. The record is prefixed with its
+// length as a 32 bit big endian integer so records can be read back from a stream.
+func createRecordBinary(format Format, now time.Time, levelString LevelString, message Message) []byte {
+	var e binaryRecordEncoder
+	if format == FormatCBOR {
+		e = &cborEncoder{}
+	} else {
+		e = &msgpackEncoder{}
+	}
+	labels := message.Labels()
+	e.writeMapHeader(5)
+	e.writeString("timestamp")
+	e.writeTimestamp(now)
+	e.writeString("level")
+	e.writeString(string(levelString))
+	e.writeString("code")
+	e.writeString(message.Code())
+	e.writeString("message")
+	e.writeString(message.Explanation())
+	e.writeString("details")
+	e.writeMapHeader(len(labels))
+	for _, name := range sortedLabelNames(labels) {
+		e.writeString(string(name))
+		e.writeValue(labels[name])
+	}
+	record := e.bytes()
+	frame := make([]byte, 0, 4+len(record))
+	frame = appendUint32(frame, uint32(len(record)))
+	return append(frame, record...)
+}
+
+func sortedLabelNames(labels Labels) []LabelName {
+	names := make([]LabelName, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// LogEntry is a log message read back from a log file written in the ljson or a binary format.
+type LogEntry struct {
+	// Time is the time the message was logged at.
+	Time time.Time
+	// Level is the level the message was logged at.
+	Level Level
+	// Message is the logged message including the labels.
+	Message Message
+}
+
+// Decoder reads log messages written in the ljson, cbor or msgpack formats.
+type Decoder interface {
+	// Decode reads the next log message. It returns io.EOF if there are no more messages.
+	Decode() (LogEntry, error)
+}
+
+// NewDecoder creates a decoder reading messages in the specified format from the reader. The ljson format is decoded
+// with the default field names, use NewLJSONDecoder for customized ljson formats.
+func NewDecoder(r io.Reader, format Format) (Decoder, error) {
+	switch format {
+	case FormatLJSON:
+		return NewLJSONDecoder(r, Config{}), nil
+	case FormatCBOR:
+		return &binaryDecoder{r: r, newDecoder: func(r io.Reader) valueDecoder {
+			return newCBORDecoder(r)
+		}}, nil
+	case FormatMsgpack:
+		return &binaryDecoder{r: r, newDecoder: func(r io.Reader) valueDecoder {
+			return newMsgpackDecoder(r)
+		}}, nil
+	default:
+		return nil, fmt.Errorf("log format does not support decoding: %s", format)
+	}
+}
+
+type valueDecoder interface {
+	decode() (interface{}, error)
+}
+
+// maxBinaryRecordSize is the largest record the decoder accepts. Larger length prefixes indicate a corrupt stream and
+// are rejected before allocating the record. Lengths of items within a record are limited to the same size.
+const maxBinaryRecordSize = 16 * 1024 * 1024
+
+type binaryDecoder struct {
+	r          io.Reader
+	newDecoder func(r io.Reader) valueDecoder
+}
+
+func (b *binaryDecoder) Decode() (LogEntry, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(b.r, header); err != nil {
+		return LogEntry{}, err
+	}
+	size := binary.BigEndian.Uint32(header)
+	if size > maxBinaryRecordSize {
+		return LogEntry{}, fmt.Errorf("invalid log record: %d bytes exceeds the maximum of %d", size, maxBinaryRecordSize)
+	}
+	record := make([]byte, size)
+	if _, err := io.ReadFull(b.r, record); err != nil {
+		return LogEntry{}, unexpectedEOF(err)
+	}
+	value, err := b.newDecoder(bytes.NewReader(record)).decode()
+	if err != nil {
+		return LogEntry{}, fmt.Errorf("failed to decode log record (%w)", unexpectedEOF(err))
+	}
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return LogEntry{}, fmt.Errorf("invalid log record: expected map, got %T", value)
+	}
+	return newLogEntry(fields)
+}
+
+// unexpectedEOF converts io.EOF to io.ErrUnexpectedEOF since it indicates a truncated record.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func newLogEntry(fields map[string]interface{}) (LogEntry, error) {
+	timestamp, ok := fields["timestamp"].(time.Time)
+	if !ok {
+		return LogEntry{}, fmt.Errorf("invalid log record: missing or invalid timestamp")
+	}
+	levelString, _ := fields["level"].(string)
+	level, err := LevelString(levelString).ToLevel()
+	if err != nil {
+		return LogEntry{}, fmt.Errorf("invalid log record (%w)", err)
+	}
+	code, _ := fields["code"].(string)
+	explanation, _ := fields["message"].(string)
+	details, _ := fields["details"].(map[string]interface{})
+	labels := make(map[LabelName]LabelValue, len(details))
+	for name, value := range details {
+		labels[LabelName(name)] = value
+	}
+	return LogEntry{
+		Time:  timestamp,
+		Level: level,
+		Message: &message{
+			code:        code,
+			userMessage: getDefaultUserMessage(code),
+			explanation: explanation,
+			labels:      labels,
+		},
+	}, nil
+}
@@ -0,0 +1,139 @@
+package log_test
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func TestBinaryFormatsRoundTrip(t *testing.T) {
+	now := time.Date(2021, 3, 4, 5, 6, 7, 890123456, time.UTC)
+	for _, format := range []log.Format{log.FormatCBOR, log.FormatMsgpack} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := log.MustNewLogger(log.Config{
+				Level:       log.LevelDebug,
+				Format:      format,
+				Destination: log.DestinationStdout,
+				Stdout:      &buf,
+				Clock: func() time.Time {
+					return now
+				},
+			})
+			logger.Warning(
+				log.NewMessage(log.MTest, "Hello %s!", "world").
+					Label("username", "foo").
+					Label("port", 2222).
+					Label("negative", -5).
+					Label("ratio", 0.5).
+					Label("success", false).
+					Label("tags", []string{"a", "b"}),
+			)
+			logger.Debug(log.NewMessage("SECOND", "Second message"))
+
+			decoder, err := log.NewDecoder(&buf, format)
+			assert.NoError(t, err)
+
+			entry, err := decoder.Decode()
+			assert.NoError(t, err)
+			assert.True(t, now.Equal(entry.Time))
+			assert.Equal(t, log.LevelWarning, entry.Level)
+			assert.Equal(t, log.MTest, entry.Message.Code())
+			assert.Equal(t, "Hello world!", entry.Message.Explanation())
+			assert.Equal(t, log.Labels{
+				"username": "foo",
+				"port":     int64(2222),
+				"negative": int64(-5),
+				"ratio":    0.5,
+				"success":  false,
+				"tags":     []interface{}{"a", "b"},
+			}, entry.Message.Labels())
+
+			entry, err = decoder.Decode()
+			assert.NoError(t, err)
+			assert.Equal(t, log.LevelDebug, entry.Level)
+			assert.Equal(t, "SECOND", entry.Message.Code())
+
+			_, err = decoder.Decode()
+			assert.Equal(t, io.EOF, err)
+		})
+	}
+}
+
+func TestBinaryFormatTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.MustNewLogger(log.Config{
+		Level:       log.LevelDebug,
+		Format:      log.FormatCBOR,
+		Destination: log.DestinationStdout,
+		Stdout:      &buf,
+	})
+	logger.Info(log.NewMessage(log.MTest, "Hello world!"))
+
+	decoder, err := log.NewDecoder(bytes.NewReader(buf.Bytes()[:buf.Len()-3]), log.FormatCBOR)
+	assert.NoError(t, err)
+	_, err = decoder.Decode()
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
+func TestBinaryDecoderRecordTooLarge(t *testing.T) {
+	for _, format := range []log.Format{log.FormatCBOR, log.FormatMsgpack} {
+		t.Run(string(format), func(t *testing.T) {
+			decoder, err := log.NewDecoder(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff}), format)
+			assert.NoError(t, err)
+			_, err = decoder.Decode()
+			assert.Error(t, err)
+			assert.NotEqual(t, io.ErrUnexpectedEOF, err)
+		})
+	}
+
+	// A CBOR array claiming 2^32 elements within a small record.
+	record := []byte{0x9a, 0xff, 0xff, 0xff, 0xff}
+	decoder, err := log.NewDecoder(bytes.NewReader(append([]byte{0, 0, 0, byte(len(record))}, record...)), log.FormatCBOR)
+	assert.NoError(t, err)
+	_, err = decoder.Decode()
+	assert.Error(t, err)
+}
+
+func TestBinaryDecoderLargeCollectionLength(t *testing.T) {
+	for name, test := range map[string]struct {
+		format log.Format
+		record []byte
+	}{
+		"cbor array":    {log.FormatCBOR, []byte{0x9a, 0x00, 0xff, 0xff, 0xff}},
+		"cbor map":      {log.FormatCBOR, []byte{0xba, 0x00, 0xff, 0xff, 0xff}},
+		"msgpack array": {log.FormatMsgpack, []byte{0xdd, 0x00, 0xff, 0xff, 0xff}},
+		"msgpack map":   {log.FormatMsgpack, []byte{0xdf, 0x00, 0xff, 0xff, 0xff}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			data := append([]byte{0, 0, 0, byte(len(test.record))}, test.record...)
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			decoder, err := log.NewDecoder(bytes.NewReader(data), test.format)
+			assert.NoError(t, err)
+			_, err = decoder.Decode()
+			runtime.ReadMemStats(&after)
+			assert.Error(t, err)
+			// The declared length must not be preallocated before the items are read.
+			assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1024*1024))
+		})
+	}
+}
+
+func TestBinaryFormatUnsupportedDestination(t *testing.T) {
+	config := log.Config{
+		Level:       log.LevelDebug,
+		Format:      log.FormatMsgpack,
+		Destination: log.DestinationSyslog,
+	}
+	assert.Error(t, config.Validate())
+
+	_, err := log.NewDecoder(&bytes.Buffer{}, log.FormatText)
+	assert.Error(t, err)
+}
@@ -0,0 +1,110 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// siemSeverity maps the syslog levels to the 1-10 severity scale used by the CEF and LEEF formats.
+var siemSeverity = map[LevelString]int{
+	LevelDebugString:     1,
+	LevelInfoString:      2,
+	LevelNoticeString:    3,
+	LevelWarningString:   5,
+	LevelErrorString:     7,
+	LevelCriticalString:  8,
+	LevelAlertString:     9,
+	LevelEmergencyString: 10,
+}
+
+var cefHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+var cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
+var leefHeaderEscaper = cefHeaderEscaper
+var leefAttributeEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\r", `\r`, "\n", `\n`)
+
+// createLineCEF creates a line in the ArcSight Common Event Format:
+//
+// CEF:0|Vendor|Product|Version|Code|UserMessage|Severity|rt=... msg=... label=value
+func createLineCEF(now time.Time, levelString LevelString, message Message, device DeviceConfig) []byte {
+	header := []string{
+		"CEF:0",
+		cefHeaderEscaper.Replace(device.Vendor),
+		cefHeaderEscaper.Replace(device.Product),
+		cefHeaderEscaper.Replace(device.Version),
+		cefHeaderEscaper.Replace(message.Code()),
+		cefHeaderEscaper.Replace(message.UserMessage()),
+		fmt.Sprintf("%d", siemSeverity[levelString]),
+	}
+	extensions := []string{
+		fmt.Sprintf("rt=%d", now.UnixNano()/int64(time.Millisecond)),
+		"msg=" + cefExtensionEscaper.Replace(message.Explanation()),
+	}
+	for _, kv := range siemLabels(message.Labels(), "rt", "msg") {
+		extensions = append(extensions, kv[0]+"="+cefExtensionEscaper.Replace(kv[1]))
+	}
+	return []byte(strings.Join(header, "|") + "|" + strings.Join(extensions, " "))
+}
+
+// createLineLEEF creates a tab-delimited line in the IBM Log Event Extended Format version 1.0:
+//
+// LEEF:1.0|Vendor|Product|Version|Code|devTime=...	sev=...	name=...	msg=...	label=value
+func createLineLEEF(now time.Time, levelString LevelString, message Message, device DeviceConfig) []byte {
+	header := []string{
+		"LEEF:1.0",
+		leefHeaderEscaper.Replace(device.Vendor),
+		leefHeaderEscaper.Replace(device.Product),
+		leefHeaderEscaper.Replace(device.Version),
+		leefHeaderEscaper.Replace(message.Code()),
+	}
+	attributes := []string{
+		fmt.Sprintf("devTime=%d", now.UnixNano()/int64(time.Millisecond)),
+		fmt.Sprintf("sev=%d", siemSeverity[levelString]),
+		"name=" + leefAttributeEscaper.Replace(message.UserMessage()),
+		"msg=" + leefAttributeEscaper.Replace(message.Explanation()),
+	}
+	for _, kv := range siemLabels(message.Labels(), "devTime", "sev", "name", "msg") {
+		attributes = append(attributes, kv[0]+"="+leefAttributeEscaper.Replace(kv[1]))
+	}
+	return []byte(strings.Join(header, "|") + "|" + strings.Join(attributes, "\t"))
+}
+
+// siemFallbackKey is the key of labels whose names contain no letters or digits.
+const siemFallbackKey = "label"
+
+// siemLabels returns the labels as key-value pairs sorted by name. Both formats only allow alphanumeric keys, so other
+// characters are removed from the label names. Labels without any letters or digits are written under the fallback
+// keys label1, label2 and so on. Keys that collide with a reserved key of the format or with an earlier label, e.g.
+// remote-addr and remoteaddr, get a numeric suffix.
+func siemLabels(labels Labels, reserved ...string) [][2]string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	used := map[string]bool{}
+	for _, key := range reserved {
+		used[key] = true
+	}
+	result := make([][2]string, 0, len(labels))
+	for _, name := range names {
+		key := strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+				return r
+			}
+			return -1
+		}, name)
+		unique := key
+		if key == "" {
+			key = siemFallbackKey
+			unique = siemFallbackKey + "1"
+		}
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s%d", key, i)
+		}
+		used[unique] = true
+		result = append(result, [2]string{unique, fmt.Sprintf("%v", labels[LabelName(name)])})
+	}
+	return result
+}
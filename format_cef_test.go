@@ -0,0 +1,120 @@
+package log_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/containerssh/structutils"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func newSIEMTestMessage() log.Message {
+	return log.UserMessage(log.MTest, "Login | failed", "Invalid password\nfor user=foo").
+		Label("username", `foo\bar`).
+		Label("remote-addr", "192.0.2.1")
+}
+
+func TestCEFFormat(t *testing.T) {
+	config := log.Config{}
+	structutils.Defaults(&config)
+	config.Format = log.FormatCEF
+	config.Destination = log.DestinationFile
+	config.File = filepath.Join(t.TempDir(), "test.log")
+	config.Device.Version = "1.0"
+	logger := log.MustNewLogger(config)
+	logger.Error(newSIEMTestMessage())
+	assert.NoError(t, logger.Close())
+
+	data, err := ioutil.ReadFile(config.File)
+	assert.NoError(t, err)
+	assert.Regexp(
+		t,
+		regexp.MustCompile(
+			`^CEF:0\|ContainerSSH\|ContainerSSH\|1\.0\|TEST\|Login \\\| failed\|7\|rt=\d+ `+
+				`msg=Invalid password\\nfor user\\=foo remoteaddr=192\.0\.2\.1 username=foo\\\\bar\n$`,
+		),
+		string(data),
+	)
+}
+
+func TestLEEFFormat(t *testing.T) {
+	var buf bytes.Buffer
+	config := log.Config{}
+	structutils.Defaults(&config)
+	config.Format = log.FormatLEEF
+	config.Stdout = &buf
+	logger := log.MustNewLogger(config)
+	logger.Emergency(newSIEMTestMessage())
+
+	parts := strings.Split(strings.TrimSpace(buf.String()), "\t")
+	assert.Regexp(t, regexp.MustCompile(`^LEEF:1\.0\|ContainerSSH\|ContainerSSH\|\|TEST\|devTime=\d+$`), parts[0])
+	assert.Equal(t, []string{
+		"sev=10",
+		"name=Login | failed",
+		`msg=Invalid password\nfor user=foo`,
+		"remoteaddr=192.0.2.1",
+		`username=foo\\bar`,
+	}, parts[1:])
+}
+
+func TestCEFSyslog(t *testing.T) {
+	connection, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = connection.Close()
+	}()
+
+	config := log.Config{}
+	structutils.Defaults(&config)
+	config.Format = log.FormatCEF
+	config.Destination = log.DestinationSyslog
+	config.Syslog.Destination = connection.LocalAddr().String()
+	logger := log.MustNewLogger(config)
+	logger.Error(newSIEMTestMessage())
+	assert.NoError(t, logger.Close())
+
+	buf := make([]byte, 4096)
+	n, _, err := connection.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^<35>.* ContainerSSH: CEF:0\|ContainerSSH\|`), string(buf[:n]))
+}
+
+func TestSIEMLabelCollisions(t *testing.T) {
+	var buf bytes.Buffer
+	config := log.Config{}
+	structutils.Defaults(&config)
+	config.Format = log.FormatLEEF
+	config.Stdout = &buf
+	logger := log.MustNewLogger(config)
+	logger.Error(
+		log.NewMessage(log.MTest, "Hello world!").
+			Label("remote-addr", "192.0.2.1").
+			Label("remoteaddr", "192.0.2.2").
+			Label("msg", "label msg").
+			Label("sev", "label sev").
+			Label("-", "dash").
+			Label("_", "underscore"),
+	)
+
+	parts := strings.Split(strings.TrimSpace(buf.String()), "\t")
+	assert.Equal(t, []string{
+		"sev=7",
+		"name=Internal Error",
+		"msg=Hello world!",
+		"label1=dash",
+		"label2=underscore",
+		"msg2=label msg",
+		"remoteaddr=192.0.2.1",
+		"remoteaddr2=192.0.2.2",
+		"sev2=label sev",
+	}, parts[1:])
+}
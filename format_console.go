@@ -0,0 +1,112 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	consoleColorReset = "\033[0m"
+	consoleColorDim   = "\033[0;37m"
+	// consoleLevelWidth is the width of the longest level name.
+	consoleLevelWidth = 7
+	// consoleCodeWidth is the minimum width of the code column. Shorter codes are padded, longer codes are printed in
+	// full so they can still be searched for.
+	consoleCodeWidth = 20
+)
+
+// ConsoleRenderer renders messages in the aligned columns of the console format. It can be used to pretty-print
+// messages read back using a Decoder in the same way the console format writes them.
+type ConsoleRenderer struct {
+	// Color colors the output according to the level.
+	Color bool
+	// HideLabels omits the labels of the messages.
+	HideLabels bool
+	// Descriptions prints the description of the message code from the registry on the line after the message.
+	Descriptions bool
+}
+
+// NewConsoleRenderer creates a renderer for the specified output. Colors are only used if the output is a terminal and
+// the NO_COLOR environment variable is not set.
+func NewConsoleRenderer(output io.Writer) ConsoleRenderer {
+	return ConsoleRenderer{
+		Color: os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" && isTerminal(output),
+	}
+}
+
+func isTerminal(output io.Writer) bool {
+	file, ok := output.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// Render returns the message with the already formatted timestamp as a line without the trailing newline.
+// Explanations spanning multiple lines are indented to the message column.
+func (r ConsoleRenderer) Render(timestamp string, levelString LevelString, message Message) string {
+	prefix := fmt.Sprintf(
+		"%s %-*s %-*s ",
+		timestamp,
+		consoleLevelWidth,
+		levelString,
+		consoleCodeWidth,
+		message.Code(),
+	)
+	indent := strings.Repeat(" ", utf8.RuneCountInString(prefix))
+	explanation := strings.Join(strings.Split(message.Explanation(), "\n"), "\n"+indent)
+	details := r.details(message, indent)
+
+	if !r.Color {
+		return prefix + explanation + details
+	}
+	format := logLevelConfig[levelString]
+	return fmt.Sprintf(
+		"%s%s%s %s%s%s%s%s%s",
+		format.symbolColor,
+		format.symbol,
+		consoleColorReset,
+		format.color,
+		prefix,
+		explanation,
+		consoleColorReset,
+		consoleColorDim,
+		details,
+	) + consoleColorReset
+}
+
+// details returns the labels and the description of the message code.
+func (r ConsoleRenderer) details(message Message, indent string) string {
+	result := ""
+	if labels := message.Labels(); !r.HideLabels && len(labels) > 0 {
+		result += "  " + strings.Join(formatTextLabels(labels), " ")
+	}
+	if info, ok := GetMessageCodeInfo(message.Code()); r.Descriptions && ok && info.Description != "" {
+		result += "\n" + indent + info.Description
+	}
+	return result
+}
+
+// newConsoleFormatter creates a formatter for human-friendly console output.
+func newConsoleFormatter(output io.Writer) *consoleFormatter {
+	return &consoleFormatter{
+		renderer: NewConsoleRenderer(output),
+	}
+}
+
+// consoleFormatter formats messages in aligned columns.
+type consoleFormatter struct {
+	renderer ConsoleRenderer
+}
+
+// createLine formats a message.
+func (c *consoleFormatter) createLine(timestamp string, levelString LevelString, message Message) []byte {
+	return []byte(c.renderer.Render(timestamp, levelString, message))
+}
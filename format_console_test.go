@@ -0,0 +1,77 @@
+package log_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func TestConsoleFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.MustNewLogger(log.Config{
+		Level:       log.LevelDebug,
+		Format:      log.FormatConsole,
+		Destination: log.DestinationStdout,
+		Stdout:      &buf,
+		Timestamp: log.TimestampConfig{
+			Format: log.TimestampFormatCustom,
+			Layout: "2006-01-02 15:04:05",
+		},
+		Clock: func() time.Time {
+			return time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+		},
+	})
+	logger.Warning(
+		log.NewMessage("LONG_MESSAGE_CODE", "First line\nSecond line").
+			Label("username", "foo").
+			Label("remoteAddr", "192.0.2.1:2222").
+			Label("reason", "invalid password"),
+	)
+	logger.Info(log.NewMessage(log.MTest, "Hello world!"))
+	logger.Info(log.NewMessage("VERY_LONG_MESSAGE_CODE", "Not truncated"))
+
+	lines := strings.Split(buf.String(), "\n")
+	assert.Len(t, lines, 5)
+	// Colors are disabled since the output is not a terminal.
+	assert.NotContains(t, buf.String(), "\033[")
+	assert.Equal(t, "2021-03-04 05:06:07 warning LONG_MESSAGE_CODE    First line", lines[0])
+	assert.Equal(
+		t,
+		strings.Repeat(" ", 49)+`Second line  reason="invalid password" remoteAddr=192.0.2.1:2222 username=foo`,
+		lines[1],
+	)
+	assert.Equal(t, "2021-03-04 05:06:07 info    TEST                 Hello world!", lines[2])
+	assert.Equal(t, "2021-03-04 05:06:07 info    VERY_LONG_MESSAGE_CODE Not truncated", lines[3])
+}
+
+func TestConsoleRenderer(t *testing.T) {
+	msg := log.NewMessage(log.MTest, "Hello world!").Label("username", "foo")
+
+	assert.Equal(
+		t,
+		"2021-03-04 05:06:07 info    TEST                 Hello world!  username=foo",
+		log.ConsoleRenderer{}.Render("2021-03-04 05:06:07", log.LevelInfoString, msg),
+	)
+	assert.Equal(
+		t,
+		"2021-03-04 05:06:07 info    TEST                 Hello world!\n"+strings.Repeat(" ", 49)+
+			"This is message that should only be seen in unit and component tests, never in production.",
+		log.ConsoleRenderer{HideLabels: true, Descriptions: true}.Render(
+			"2021-03-04 05:06:07",
+			log.LevelInfoString,
+			msg,
+		),
+	)
+	colored := log.ConsoleRenderer{Color: true}.Render("2021-03-04 05:06:07", log.LevelInfoString, msg)
+	assert.True(t, strings.HasPrefix(colored, "\033[34m"))
+	assert.True(t, strings.HasSuffix(colored, "\033[0m"))
+}
+
+func TestConsoleRendererNotTerminal(t *testing.T) {
+	assert.False(t, log.NewConsoleRenderer(&bytes.Buffer{}).Color)
+}
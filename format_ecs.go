@@ -0,0 +1,83 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ecsVersion is the Elastic Common Schema version the ecs format conforms to.
+const ecsVersion = "1.12.0"
+
+// ecsNamespace is the object the labels without an ECS equivalent are placed in.
+const ecsNamespace = "containerssh"
+
+// ecsLabelFields maps well-known label names to their ECS fields.
+var ecsLabelFields = map[LabelName]string{
+	"username":     "user.name",
+	"connectionId": "transaction.id",
+}
+
+// createLineECS creates a JSON document conforming to the Elastic Common Schema. All ECS fields are written as nested
+// objects, and labels without an ECS equivalent are only written to the namespace object, so a label can never produce
+// a second, conflicting copy of a field.
+func createLineECS(now time.Time, levelString LevelString, message Message) ([]byte, error) {
+	doc := map[string]interface{}{
+		"@timestamp": now.Format("2006-01-02T15:04:05.000Z07:00"),
+		"message":    message.Explanation(),
+	}
+	setECSField(doc, "log.level", string(levelString))
+	setECSField(doc, "ecs.version", ecsVersion)
+	setECSField(doc, "event.code", message.Code())
+	namespace := map[string]interface{}{}
+	for label, value := range message.Labels() {
+		if label == "remoteAddr" {
+			setECSSource(doc, value)
+			continue
+		}
+		if field, ok := ecsLabelFields[label]; ok {
+			setECSField(doc, field, value)
+			continue
+		}
+		namespace[string(label)] = value
+	}
+	if len(namespace) > 0 {
+		doc[ecsNamespace] = namespace
+	}
+	return json.Marshal(doc)
+}
+
+// setECSSource sets the source.address field and, if the address can be parsed, the source.ip and source.port
+// fields.
+func setECSSource(doc map[string]interface{}, value LabelValue) {
+	address := fmt.Sprintf("%v", value)
+	setECSField(doc, "source.address", address)
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		setECSField(doc, "source.ip", ip.String())
+	}
+	if p, err := strconv.ParseUint(port, 10, 16); err == nil {
+		setECSField(doc, "source.port", p)
+	}
+}
+
+// setECSField sets a dotted field name as a nested object in the document.
+func setECSField(doc map[string]interface{}, field string, value interface{}) {
+	parts := strings.Split(field, ".")
+	current := doc
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+}
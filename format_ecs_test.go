@@ -0,0 +1,87 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func TestECSFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.MustNewLogger(log.Config{
+		Level:       log.LevelDebug,
+		Format:      log.FormatECS,
+		Destination: log.DestinationStdout,
+		Stdout:      &buf,
+	})
+	logger.Warning(
+		log.NewMessage(log.MTest, "Hello world!").
+			Label("remoteAddr", "192.0.2.1:2222").
+			Label("username", "foo").
+			Label("connectionId", "abcd").
+			Label("backend", "docker"),
+	)
+
+	data := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &data))
+	assert.NotEmpty(t, data["@timestamp"])
+	assert.Equal(t, map[string]interface{}{"level": "warning"}, data["log"])
+	assert.Equal(t, map[string]interface{}{"version": "1.12.0"}, data["ecs"])
+	assert.Equal(t, "Hello world!", data["message"])
+	assert.Equal(t, map[string]interface{}{"code": log.MTest}, data["event"])
+	assert.Equal(t, map[string]interface{}{
+		"address": "192.0.2.1:2222",
+		"ip":      "192.0.2.1",
+		"port":    float64(2222),
+	}, data["source"])
+	assert.Equal(t, map[string]interface{}{"name": "foo"}, data["user"])
+	assert.Equal(t, map[string]interface{}{"id": "abcd"}, data["transaction"])
+	assert.Equal(t, map[string]interface{}{"backend": "docker"}, data["containerssh"])
+}
+
+func TestECSFormatLabelsCannotOverrideFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.MustNewLogger(log.Config{
+		Level:       log.LevelDebug,
+		Format:      log.FormatECS,
+		Destination: log.DestinationStdout,
+		Stdout:      &buf,
+	})
+	logger.Warning(
+		log.NewMessage(log.MTest, "Hello world!").
+			Label("log.level", "debug").
+			Label("ecs.version", "0.0.1").
+			Label("log", "foo").
+			Label("ecs", "bar"),
+	)
+
+	// Decode the top level keys one by one, since unmarshalling into a map silently keeps only the last duplicate.
+	decoder := json.NewDecoder(bytes.NewReader(buf.Bytes()))
+	_, err := decoder.Token()
+	assert.NoError(t, err)
+	var keys []string
+	data := map[string]interface{}{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		assert.NoError(t, err)
+		key, ok := token.(string)
+		assert.True(t, ok)
+		var value interface{}
+		assert.NoError(t, decoder.Decode(&value))
+		keys = append(keys, key)
+		data[key] = value
+	}
+	assert.ElementsMatch(t, []string{"@timestamp", "message", "log", "ecs", "event", "containerssh"}, keys)
+	assert.Equal(t, map[string]interface{}{"level": "warning"}, data["log"])
+	assert.Equal(t, map[string]interface{}{"version": "1.12.0"}, data["ecs"])
+	assert.Equal(t, map[string]interface{}{
+		"log.level":   "debug",
+		"ecs.version": "0.0.1",
+		"log":         "foo",
+		"ecs":         "bar",
+	}, data["containerssh"])
+}
@@ -0,0 +1,93 @@
+package log
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// gcpSeverity maps the syslog levels to the Google Cloud Logging severities.
+var gcpSeverity = map[LevelString]string{
+	LevelDebugString:     "DEBUG",
+	LevelInfoString:      "INFO",
+	LevelNoticeString:    "NOTICE",
+	LevelWarningString:   "WARNING",
+	LevelErrorString:     "ERROR",
+	LevelCriticalString:  "CRITICAL",
+	LevelAlertString:     "ALERT",
+	LevelEmergencyString: "EMERGENCY",
+}
+
+// gcpLine is a structured log entry as recognized by the Google Cloud Logging agents.
+type gcpLine struct {
+	Severity       string             `json:"severity"`
+	Message        string             `json:"message"`
+	Time           string             `json:"time"`
+	Code           string             `json:"code"`
+	Labels         map[string]string  `json:"logging.googleapis.com/labels,omitempty"`
+	SourceLocation *gcpSourceLocation `json:"logging.googleapis.com/sourceLocation,omitempty"`
+	Trace          string             `json:"logging.googleapis.com/trace,omitempty"`
+	SpanID         string             `json:"logging.googleapis.com/spanId,omitempty"`
+}
+
+type gcpSourceLocation struct {
+	File     string `json:"file"`
+	Line     string `json:"line"`
+	Function string `json:"function"`
+}
+
+// createLineGCP creates a JSON line in the structured logging format of Google Cloud Logging.
+func createLineGCP(now time.Time, levelString LevelString, message Message, config GCPConfig) ([]byte, error) {
+	labels := make(Labels, len(message.Labels()))
+	for name, value := range message.Labels() {
+		labels[name] = value
+	}
+	line := gcpLine{
+		Severity:       gcpSeverity[levelString],
+		Message:        message.Explanation(),
+		Time:           now.Format(time.RFC3339Nano),
+		Code:           message.Code(),
+		SourceLocation: getGCPSourceLocation(),
+	}
+	traceID, spanID := extractTraceContext(labels, config.TraceIDLabel, config.SpanIDLabel)
+	if traceID != nil {
+		line.Trace = hex.EncodeToString(traceID)
+		if config.ProjectID != "" {
+			line.Trace = fmt.Sprintf("projects/%s/traces/%s", config.ProjectID, line.Trace)
+		}
+	}
+	if spanID != nil {
+		line.SpanID = hex.EncodeToString(spanID)
+	}
+	if len(labels) > 0 {
+		// Cloud Logging only accepts string label values.
+		line.Labels = make(map[string]string, len(labels))
+		for name, value := range labels {
+			line.Labels[string(name)] = fmt.Sprintf("%v", value)
+		}
+	}
+	return json.Marshal(line)
+}
+
+// getGCPSourceLocation returns the first caller outside of this package, which is the code that logged the message.
+func getGCPSourceLocation() *gcpSourceLocation {
+	pc := make([]uintptr, 16)
+	n := runtime.Callers(2, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "github.com/containerssh/log.") {
+			return &gcpSourceLocation{
+				File:     frame.File,
+				Line:     fmt.Sprintf("%d", frame.Line),
+				Function: frame.Function,
+			}
+		}
+		if !more {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/containerssh/structutils"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func TestGCPFormat(t *testing.T) {
+	var buf bytes.Buffer
+	config := log.Config{}
+	structutils.Defaults(&config)
+	config.Format = log.FormatGCP
+	config.Stdout = &buf
+	config.GCP.ProjectID = "test-project"
+	logger := log.MustNewLogger(config)
+	logger.Critical(
+		log.NewMessage(log.MTest, "Hello world!").
+			Label("username", "foo").
+			Label("count", 5).
+			Label("traceId", "0af7651916cd43dd8448eb211c80319c").
+			Label("spanId", "b7ad6b7169203331"),
+	)
+
+	data := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &data))
+	assert.Equal(t, "CRITICAL", data["severity"])
+	assert.Equal(t, "Hello world!", data["message"])
+	assert.Equal(t, log.MTest, data["code"])
+	assert.NotEmpty(t, data["time"])
+	assert.Equal(t, map[string]interface{}{
+		"username": "foo",
+		"count":    "5",
+	}, data["logging.googleapis.com/labels"])
+	assert.Equal(t, "projects/test-project/traces/0af7651916cd43dd8448eb211c80319c", data["logging.googleapis.com/trace"])
+	assert.Equal(t, "b7ad6b7169203331", data["logging.googleapis.com/spanId"])
+	sourceLocation := data["logging.googleapis.com/sourceLocation"].(map[string]interface{})
+	assert.Equal(t, "github.com/containerssh/log_test.TestGCPFormat", sourceLocation["function"])
+}
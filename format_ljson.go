@@ -0,0 +1,133 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// newLJSONFormatter creates a formatter for the ljson format. The host name and PID are determined once when the
+// formatter is created.
+func newLJSONFormatter(config LJSONConfig) (*ljsonFormatter, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	f := &ljsonFormatter{
+		config: config,
+		fields: config.Fields.withDefaults(),
+		prefix: config.CollisionPrefix,
+	}
+	if f.prefix == "" {
+		f.prefix = "label_"
+	}
+	if config.Hostname {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine host name for the ljson format (%w)", err)
+		}
+		f.hostname = hostname
+	}
+	return f, nil
+}
+
+type ljsonFormatter struct {
+	config   LJSONConfig
+	fields   LJSONFieldsConfig
+	prefix   string
+	hostname string
+}
+
+// ljsonField is a single key-value pair in the JSON object. A list is used instead of a map to keep the field order
+// stable.
+type ljsonField struct {
+	name  string
+	value interface{}
+}
+
+func (l *ljsonFormatter) createLine(timestamp interface{}, levelString LevelString, message Message) ([]byte, error) {
+	fields := []ljsonField{
+		{l.fields.Timestamp, timestamp},
+		{l.fields.Level, string(levelString)},
+		{l.fields.Code, message.Code()},
+		{l.fields.Message, message.Explanation()},
+	}
+	if l.config.UserMessage {
+		fields = append(fields, ljsonField{l.fields.UserMessage, message.UserMessage()})
+	}
+	if l.config.Hostname {
+		fields = append(fields, ljsonField{l.fields.Hostname, l.hostname})
+	}
+	if l.config.PID {
+		fields = append(fields, ljsonField{l.fields.PID, os.Getpid()})
+	}
+	for _, name := range sortedKeys(l.config.Extra) {
+		fields = append(fields, ljsonField{name, l.config.Extra[name]})
+	}
+	if l.config.Labels == LJSONLabelModeFlat {
+		fields = l.appendFlatLabels(fields, message.Labels())
+	} else {
+		details := map[string]interface{}{}
+		for label, value := range message.Labels() {
+			details[string(label)] = value
+		}
+		fields = append(fields, ljsonField{l.fields.Details, details})
+	}
+	return encodeLJSONFields(fields)
+}
+
+// appendFlatLabels adds the labels to the top level. Labels colliding with an existing field are prefixed until the
+// name is unique.
+func (l *ljsonFormatter) appendFlatLabels(fields []ljsonField, labels Labels) []ljsonField {
+	used := make(map[string]bool, len(fields)+len(labels))
+	for _, field := range fields {
+		used[field.name] = true
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		key := name
+		for used[key] {
+			key = l.prefix + key
+		}
+		used[key] = true
+		fields = append(fields, ljsonField{key, labels[LabelName(name)]})
+	}
+	return fields
+}
+
+func encodeLJSONFields(fields []ljsonField) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(field.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func sortedKeys(data map[string]string) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
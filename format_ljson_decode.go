@@ -0,0 +1,167 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LineDecodeError is returned by the ljson decoder if a line is not a valid log message. Decoding can continue with
+// the next line.
+type LineDecodeError struct {
+	// Line is the line that could not be decoded without the trailing newline.
+	Line string
+	// Cause is the reason the line could not be decoded.
+	Cause error
+}
+
+func (e *LineDecodeError) Error() string {
+	return fmt.Sprintf("invalid log line (%v): %s", e.Cause, e.Line)
+}
+
+// Unwrap returns the reason the line could not be decoded.
+func (e *LineDecodeError) Unwrap() error {
+	return e.Cause
+}
+
+// NewLJSONDecoder creates a decoder for messages written in the ljson format by a logger with the specified
+// configuration. The field names, the placement of the labels and the timestamp format are taken from the
+// configuration, so log files written with a customized ljson format can be read back.
+//
+// Flattened labels are returned with the names they were written with. A label renamed with the collision prefix
+// cannot be told apart from a label that has the prefixed name, so the prefix is not removed.
+func NewLJSONDecoder(r io.Reader, config Config) Decoder {
+	return &ljsonDecoder{
+		reader:     bufio.NewReader(r),
+		config:     config.LJSON,
+		fields:     config.LJSON.Fields.withDefaults(),
+		timeSource: newTimeSource(config),
+	}
+}
+
+type ljsonDecoder struct {
+	reader     *bufio.Reader
+	config     LJSONConfig
+	fields     LJSONFieldsConfig
+	timeSource *timeSource
+}
+
+func (d *ljsonDecoder) Decode() (LogEntry, error) {
+	for {
+		line, err := d.reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) == 0 {
+			if err != nil {
+				return LogEntry{}, err
+			}
+			continue
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return LogEntry{}, err
+		}
+		entry, err := d.decodeLine(line)
+		if err != nil {
+			return LogEntry{}, &LineDecodeError{Line: strings.TrimRight(string(line), "\r\n"), Cause: err}
+		}
+		return entry, nil
+	}
+}
+
+func (d *ljsonDecoder) decodeLine(line []byte) (LogEntry, error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	fields := map[string]interface{}{}
+	if err := decoder.Decode(&fields); err != nil {
+		return LogEntry{}, err
+	}
+	timestamp, err := d.timeSource.parse(fields[d.fields.Timestamp])
+	if err != nil {
+		return LogEntry{}, err
+	}
+	levelString, _ := fields[d.fields.Level].(string)
+	level, err := LevelString(levelString).ToLevel()
+	if err != nil {
+		return LogEntry{}, err
+	}
+	code, _ := fields[d.fields.Code].(string)
+	explanation, _ := fields[d.fields.Message].(string)
+	userMessage, ok := fields[d.fields.UserMessage].(string)
+	if !d.config.UserMessage || !ok {
+		userMessage = getDefaultUserMessage(code)
+	}
+	return LogEntry{
+		Time:  timestamp,
+		Level: level,
+		Message: &message{
+			code:        code,
+			userMessage: userMessage,
+			explanation: explanation,
+			labels:      d.labels(fields),
+		},
+	}, nil
+}
+
+// labels extracts the labels from the details field, or from the top level for flat labels. Flat labels keep the
+// names they were written with, including any collision prefix.
+func (d *ljsonDecoder) labels(fields map[string]interface{}) Labels {
+	labels := Labels{}
+	if d.config.Labels != LJSONLabelModeFlat {
+		details, _ := fields[d.fields.Details].(map[string]interface{})
+		for name, value := range details {
+			labels[LabelName(name)] = fromJSONValue(value)
+		}
+		return labels
+	}
+	reserved := d.reservedFields()
+	for name, value := range fields {
+		if reserved[name] {
+			continue
+		}
+		labels[LabelName(name)] = fromJSONValue(value)
+	}
+	return labels
+}
+
+// reservedFields returns the top level fields the ljson formatter writes besides the labels.
+func (d *ljsonDecoder) reservedFields() map[string]bool {
+	reserved := map[string]bool{
+		d.fields.Timestamp: true,
+		d.fields.Level:     true,
+		d.fields.Code:      true,
+		d.fields.Message:   true,
+	}
+	reserved[d.fields.UserMessage] = d.config.UserMessage
+	reserved[d.fields.Hostname] = d.config.Hostname
+	reserved[d.fields.PID] = d.config.PID
+	for name := range d.config.Extra {
+		reserved[name] = true
+	}
+	return reserved
+}
+
+// fromJSONValue converts JSON numbers to int64 if possible and float64 otherwise.
+func fromJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = fromJSONValue(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = fromJSONValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,216 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func createLJSONLogger(buf *bytes.Buffer, config log.LJSONConfig) (log.Logger, error) {
+	return log.NewLogger(log.Config{
+		Level:       log.LevelDebug,
+		Format:      log.FormatLJSON,
+		Destination: log.DestinationStdout,
+		Stdout:      buf,
+		LJSON:       config,
+		Clock: func() time.Time {
+			return time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+		},
+	})
+}
+
+func TestLJSONDefault(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := createLJSONLogger(&buf, log.LJSONConfig{})
+	assert.NoError(t, err)
+	logger.Info(log.UserMessage(log.MTest, "Hi!", "Hello world!").Label("username", "foo"))
+	assert.Equal(
+		t,
+		`{"timestamp":"2021-03-04T05:06:07Z","level":"info","code":"TEST","message":"Hello world!",`+
+			`"details":{"username":"foo"}}`+"\n",
+		buf.String(),
+	)
+}
+
+func TestLJSONRenamedFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := createLJSONLogger(&buf, log.LJSONConfig{
+		Fields: log.LJSONFieldsConfig{
+			Timestamp:   "@timestamp",
+			Level:       "severity",
+			Details:     "labels",
+			UserMessage: "user_message",
+		},
+		UserMessage: true,
+		PID:         true,
+		Extra:       map[string]string{"service": "containerssh"},
+	})
+	assert.NoError(t, err)
+	logger.Info(log.UserMessage(log.MTest, "Hi!", "Hello world!").Label("username", "foo"))
+
+	data := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &data))
+	assert.Equal(t, map[string]interface{}{
+		"@timestamp":   "2021-03-04T05:06:07Z",
+		"severity":     "info",
+		"code":         log.MTest,
+		"message":      "Hello world!",
+		"user_message": "Hi!",
+		"pid":          float64(os.Getpid()),
+		"service":      "containerssh",
+		"labels":       map[string]interface{}{"username": "foo"},
+	}, data)
+}
+
+func TestLJSONFlatLabels(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := createLJSONLogger(&buf, log.LJSONConfig{
+		Labels: log.LJSONLabelModeFlat,
+		Extra:  map[string]string{"service": "containerssh"},
+	})
+	assert.NoError(t, err)
+	logger.Info(
+		log.NewMessage(log.MTest, "Hello world!").
+			Label("username", "foo").
+			Label("level", "custom").
+			Label("service", "other").
+			Label("label_service", "clash"),
+	)
+
+	data := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &data))
+	assert.Equal(t, map[string]interface{}{
+		"timestamp":           "2021-03-04T05:06:07Z",
+		"level":               "info",
+		"code":                log.MTest,
+		"message":             "Hello world!",
+		"service":             "containerssh",
+		"username":            "foo",
+		"label_level":         "custom",
+		"label_service":       "clash",
+		"label_label_service": "other",
+	}, data)
+}
+
+func TestLJSONInvalidConfig(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := createLJSONLogger(&buf, log.LJSONConfig{
+		Fields: log.LJSONFieldsConfig{Level: "code"},
+	})
+	assert.Error(t, err)
+
+	_, err = createLJSONLogger(&buf, log.LJSONConfig{
+		Extra: map[string]string{"message": "foo"},
+	})
+	assert.Error(t, err)
+}
+
+func TestLJSONConfigOptionalFields(t *testing.T) {
+	config := log.LJSONConfig{
+		Fields: log.LJSONFieldsConfig{UserMessage: "message", Hostname: "code", PID: "level", Details: "timestamp"},
+		Labels: log.LJSONLabelModeFlat,
+	}
+	assert.NoError(t, config.Validate())
+
+	config.Hostname = true
+	for i := 0; i < 10; i++ {
+		err := config.Validate()
+		if assert.Error(t, err) {
+			assert.Equal(t, "the ljson code and hostname fields have the same name: code", err.Error())
+		}
+	}
+}
+
+func TestLJSONDecoder(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := createLJSONLogger(&buf, log.LJSONConfig{})
+	assert.NoError(t, err)
+	logger.Warning(log.UserMessage(log.MTest, "Hi!", "Hello world!").Label("username", "foo").Label("port", 22))
+	buf.WriteString("this is not json\n\n")
+	logger.Error(log.NewMessage(log.ELogWriteFailed, "Disk full"))
+
+	decoder, err := log.NewDecoder(&buf, log.FormatLJSON)
+	assert.NoError(t, err)
+	entry, err := decoder.Decode()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), entry.Time.UTC())
+	assert.Equal(t, log.LevelWarning, entry.Level)
+	assert.Equal(t, log.MTest, entry.Message.Code())
+	assert.Equal(t, "Hello world!", entry.Message.Explanation())
+	assert.Equal(t, log.Labels{"username": "foo", "port": int64(22)}, entry.Message.Labels())
+
+	_, err = decoder.Decode()
+	var lineError *log.LineDecodeError
+	assert.True(t, errors.As(err, &lineError))
+	assert.Equal(t, "this is not json", lineError.Line)
+
+	entry, err = decoder.Decode()
+	assert.NoError(t, err)
+	assert.Equal(t, log.ELogWriteFailed, entry.Message.Code())
+	assert.Equal(t, log.LevelError, entry.Level)
+
+	_, err = decoder.Decode()
+	assert.True(t, errors.Is(err, io.EOF))
+}
+
+func TestLJSONDecoderCustomFormat(t *testing.T) {
+	var buf bytes.Buffer
+	config := log.Config{
+		Level:       log.LevelDebug,
+		Format:      log.FormatLJSON,
+		Destination: log.DestinationStdout,
+		Stdout:      &buf,
+		LJSON: log.LJSONConfig{
+			Fields:      log.LJSONFieldsConfig{Level: "severity"},
+			Labels:      log.LJSONLabelModeFlat,
+			UserMessage: true,
+			Extra:       map[string]string{"service": "containerssh"},
+		},
+		Timestamp: log.TimestampConfig{Format: log.TimestampFormatUnixMilli, UTC: true},
+		Clock: func() time.Time {
+			return time.Date(2021, 3, 4, 5, 6, 7, 8000000, time.UTC)
+		},
+	}
+	logger, err := log.NewLogger(config)
+	assert.NoError(t, err)
+	logger.Info(log.UserMessage(log.MTest, "Hi!", "Hello world!").Label("code", "foo").Label("ratio", 0.5))
+
+	entry, err := log.NewLJSONDecoder(&buf, config).Decode()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 3, 4, 5, 6, 7, 8000000, time.UTC), entry.Time)
+	assert.Equal(t, log.LevelInfo, entry.Level)
+	assert.Equal(t, "Hi!", entry.Message.UserMessage())
+	assert.Equal(t, log.Labels{"label_code": "foo", "ratio": 0.5}, entry.Message.Labels())
+}
+
+func TestLJSONDecoderFlatLabelCollisions(t *testing.T) {
+	var buf bytes.Buffer
+	config := log.Config{
+		Level:       log.LevelDebug,
+		Format:      log.FormatLJSON,
+		Destination: log.DestinationStdout,
+		Stdout:      &buf,
+		LJSON:       log.LJSONConfig{Labels: log.LJSONLabelModeFlat},
+	}
+	logger, err := log.NewLogger(config)
+	assert.NoError(t, err)
+	logger.Info(log.NewMessage(log.MTest, "Hello world!").Label("label_level", "a"))
+	logger.Info(log.NewMessage(log.MTest, "Hello world!").Label("level", "b").Label("label_level", "c"))
+
+	decoder := log.NewLJSONDecoder(&buf, config)
+	entry, err := decoder.Decode()
+	assert.NoError(t, err)
+	assert.Equal(t, log.Labels{"label_level": "a"}, entry.Message.Labels())
+
+	entry, err = decoder.Decode()
+	assert.NoError(t, err)
+	assert.Equal(t, log.Labels{"label_level": "c", "label_label_level": "b"}, entry.Message.Labels())
+}
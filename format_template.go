@@ -0,0 +1,105 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateFuncs are the helper functions available in user-supplied templates.
+var templateFuncs = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+	"quote":        strconv.Quote,
+	"upper":        strings.ToUpper,
+	"lower":        strings.ToLower,
+	"sortedLabels": sortedTemplateLabels,
+}
+
+// templateMessage is the representation of a single message passed to user-supplied templates.
+type templateMessage struct {
+	Time        time.Time
+	Level       string
+	Code        string
+	UserMessage string
+	Explanation string
+	Labels      map[string]interface{}
+}
+
+func newTemplateMessage(
+	t time.Time,
+	levelString LevelString,
+	code string,
+	userMessage string,
+	explanation string,
+	labels Labels,
+) templateMessage {
+	// Templates can only access map keys of the string type as fields.
+	templateLabels := make(map[string]interface{}, len(labels))
+	for name, value := range labels {
+		templateLabels[string(name)] = value
+	}
+	return templateMessage{
+		Time:        t,
+		Level:       string(levelString),
+		Code:        code,
+		UserMessage: userMessage,
+		Explanation: explanation,
+		Labels:      templateLabels,
+	}
+}
+
+// templateLabel is a single label returned by the sortedLabels template function.
+type templateLabel struct {
+	Name  string
+	Value interface{}
+}
+
+func sortedTemplateLabels(labels map[string]interface{}) []templateLabel {
+	result := make([]templateLabel, 0, len(labels))
+	for name, value := range labels {
+		result = append(result, templateLabel{Name: name, Value: value})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// parseLineTemplate parses the template of the template log format.
+func parseLineTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, fmt.Errorf("template log format selected but no template provided")
+	}
+	tpl, err := template.New("line").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log template (%w)", err)
+	}
+	return tpl, nil
+}
+
+// createLineTemplate renders a message through the user-supplied template.
+func createLineTemplate(tpl *template.Template, now time.Time, levelString LevelString, message Message) (
+	[]byte,
+	error,
+) {
+	wr := &bytes.Buffer{}
+	if err := tpl.Execute(wr, newTemplateMessage(
+		now,
+		levelString,
+		message.Code(),
+		message.UserMessage(),
+		message.Explanation(),
+		message.Labels(),
+	)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(wr.Bytes(), "\n"), nil
+}
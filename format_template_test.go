@@ -0,0 +1,49 @@
+package log_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func TestTemplateFormat(t *testing.T) {
+	var buf bytes.Buffer
+	config := log.Config{
+		Level:       log.LevelDebug,
+		Format:      log.FormatTemplate,
+		Template:    `{{ upper .Level }} [{{ .Code }}] {{ quote .Explanation }}{{ range sortedLabels .Labels }} {{ .Name }}={{ json .Value }}{{ end }}`,
+		Destination: log.DestinationStdout,
+		Stdout:      &buf,
+	}
+	assert.NoError(t, config.Validate())
+	logger := log.MustNewLogger(config)
+	logger.Warning(
+		log.NewMessage(log.MTest, "Hello \"world\"!").
+			Label("username", "foo").
+			Label("count", 5),
+	)
+	assert.Equal(t, "WARNING [TEST] \"Hello \\\"world\\\"!\" count=5 username=\"foo\"\n", buf.String())
+}
+
+func TestTemplateFormatInvalid(t *testing.T) {
+	for name, tpl := range map[string]string{
+		"empty":   "",
+		"invalid": "{{ .Code ",
+		"unknown": "{{ unknownFunction .Code }}",
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := log.Config{
+				Level:       log.LevelDebug,
+				Format:      log.FormatTemplate,
+				Template:    tpl,
+				Destination: log.DestinationStdout,
+			}
+			assert.Error(t, config.Validate())
+			_, err := log.NewLogger(config)
+			assert.Error(t, err)
+		})
+	}
+}
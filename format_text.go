@@ -0,0 +1,100 @@
+package log
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// formatTextLabels renders the labels as name=value pairs sorted by name.
+func formatTextLabels(labels Labels) []string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + "=" + formatTextValue(labels[LabelName(name)])
+	}
+	return parts
+}
+
+// formatTextValue renders a label value according to its type. Strings are quoted if they are empty or contain
+// characters that would make the output ambiguous, slices and maps are rendered recursively with map keys in sorted
+// order.
+func formatTextValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return quoteTextValue(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration:
+		return v.String()
+	case error:
+		return quoteTextValue(v.Error())
+	case fmt.Stringer:
+		return quoteTextValue(v.String())
+	default:
+		return formatTextReflect(reflect.ValueOf(value))
+	}
+}
+
+func formatTextReflect(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.String:
+		return quoteTextValue(v.String())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Slice, reflect.Array:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatTextValue(v.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ",") + "]"
+	case reflect.Map:
+		items := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			items = append(
+				items,
+				fmt.Sprintf("%v", key.Interface())+":"+formatTextValue(v.MapIndex(key).Interface()),
+			)
+		}
+		sort.Strings(items)
+		return "{" + strings.Join(items, ",") + "}"
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return "null"
+		}
+		return formatTextValue(v.Elem().Interface())
+	case reflect.Invalid:
+		return "null"
+	default:
+		return quoteTextValue(fmt.Sprintf("%+v", v.Interface()))
+	}
+}
+
+func quoteTextValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\r\n\"=()[]{},") {
+		return strconv.Quote(value)
+	}
+	return value
+}
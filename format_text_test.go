@@ -0,0 +1,104 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containerssh/structutils"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+type testStringer struct{}
+
+func (testStringer) String() string {
+	return "stringer value"
+}
+
+func testClock() time.Time {
+	return time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+}
+
+func newTypedLabelsMessage() log.Message {
+	port := 2222
+	return log.NewMessage(log.MTest, "Hello world!").
+		Label("string", "foo").
+		Label("quotedString", "foo bar").
+		Label("emptyString", "").
+		Label("int", 5).
+		Label("negativeInt", int64(-42)).
+		Label("uint", uint8(255)).
+		Label("float", 1.5).
+		Label("float32", float32(0.1)).
+		Label("bool", true).
+		Label("nil", nil).
+		Label("pointer", &port).
+		Label("time", time.Date(2021, 3, 4, 5, 6, 7, 890000000, time.UTC)).
+		Label("duration", 90*time.Second).
+		Label("slice", []interface{}{"a", 1, "b c"}).
+		Label("map", map[string]interface{}{"b": 2, "a": []int{1, 2}}).
+		Label("stringer", testStringer{}).
+		Label("error", errors.New("connection refused")).
+		Label("bytes", []byte("hi"))
+}
+
+func assertGolden(t *testing.T, name string, actual []byte) {
+	file := filepath.Join("testdata", name)
+	if *updateGolden {
+		assert.NoError(t, os.WriteFile(file, actual, 0644))
+	}
+	expected, err := os.ReadFile(file)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestTextLabelsGolden(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.MustNewLogger(log.Config{
+		Level:       log.LevelDebug,
+		Format:      log.FormatText,
+		Destination: log.DestinationStdout,
+		Stdout:      &buf,
+		Clock:       testClock,
+	})
+	logger.Info(newTypedLabelsMessage())
+	assertGolden(t, "labels_text.golden", buf.Bytes())
+}
+
+func TestSyslogTextLabelsGolden(t *testing.T) {
+	connection, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = connection.Close()
+	}()
+
+	config := log.Config{}
+	structutils.Defaults(&config)
+	config.Format = log.FormatText
+	config.Destination = log.DestinationSyslog
+	config.Syslog.Destination = connection.LocalAddr().String()
+	config.Level = log.LevelDebug
+	config.Clock = testClock
+	logger := log.MustNewLogger(config)
+	logger.Info(newTypedLabelsMessage())
+	assert.NoError(t, logger.Close())
+
+	assert.NoError(t, connection.SetReadDeadline(time.Now().Add(10*time.Second)))
+	buf := make([]byte, 4096)
+	n, _, err := connection.ReadFrom(buf)
+	assert.NoError(t, err)
+	assertGolden(t, "labels_syslog.golden", buf[:n])
+}
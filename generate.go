@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"fmt"
 	"go/ast"
-	"go/doc"
 	"go/parser"
 	"go/token"
 	"os"
+	"strconv"
 	"strings"
 	"text/template"
+	"unicode"
+	"unicode/utf8"
 )
 
 var messageFileTemplate = `# Message / error codes
@@ -21,85 +23,281 @@ var messageFileTemplate = `# Message / error codes
 {{end}}
 `
 
-// GetMessageCodes parses the specified go source file and returns a key-value mapping of message codes and their
-// associated description or an error.
-func GetMessageCodes(filename string) (map[string]string, error) {
-	result := map[string]string{}
-	fset := token.NewFileSet()
+// DefaultMessageCodePrefixes are the constant name prefixes used to find message codes if no filter is specified.
+var DefaultMessageCodePrefixes = []string{"E", "M", "W"}
 
-	f, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
-	if err != nil {
-		return result, fmt.Errorf("failed to parse file %s (%w)", filename, err)
+// MessageCodeFilter selects which constants are treated as message codes.
+type MessageCodeFilter struct {
+	// TypeName selects constants declared with this type, e.g. "Code". If empty, the type is not checked.
+	TypeName string
+	// Prefixes selects constants whose name starts with one of these prefixes followed by an upper case letter, e.g.
+	// "E" matches ELogWriteFailed. If both TypeName and Prefixes are empty, DefaultMessageCodePrefixes is used.
+	Prefixes []string
+}
+
+func (f MessageCodeFilter) matches(name string, typeExpr ast.Expr) bool {
+	if f.TypeName != "" {
+		ident, ok := typeExpr.(*ast.Ident)
+		if !ok || ident.Name != f.TypeName {
+			return false
+		}
+		if len(f.Prefixes) == 0 {
+			return true
+		}
+	}
+	prefixes := f.Prefixes
+	if len(prefixes) == 0 {
+		prefixes = DefaultMessageCodePrefixes
+	}
+	for _, prefix := range prefixes {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		next, _ := utf8.DecodeRuneInString(name[len(prefix):])
+		if unicode.IsUpper(next) {
+			return true
+		}
+	}
+	return false
+}
+
+// MessageCodesError lists all problems found while extracting message codes, such as undocumented constants.
+type MessageCodesError struct {
+	Problems []string
+}
+
+func (e *MessageCodesError) Error() string {
+	return fmt.Sprintf(
+		"%d problem(s) found in message codes:\n%s",
+		len(e.Problems),
+		strings.Join(e.Problems, "\n"),
+	)
+}
+
+// messageCodeCollector collects message codes and problems from multiple files.
+type messageCodeCollector struct {
+	fset     *token.FileSet
+	filter   MessageCodeFilter
+	codes    map[string]string
+	infos    map[string]MessageCodeInfo
+	declared []MessageCodeDeclaration
+	problems []string
+}
+
+// MessageCodeDeclaration is a documented message code constant found by FindMessageCodeDeclarations.
+type MessageCodeDeclaration struct {
+	// Name is the name of the constant.
+	Name string
+	// Pos is the position of the constant name in the file set the file was parsed with.
+	Pos token.Pos
+	// Info contains the code and the description and tags from the documentation.
+	Info MessageCodeInfo
+}
+
+func newMessageCodeCollector(filter MessageCodeFilter) *messageCodeCollector {
+	return &messageCodeCollector{
+		fset:   token.NewFileSet(),
+		filter: filter,
+		codes:  map[string]string{},
+		infos:  map[string]MessageCodeInfo{},
 	}
+}
 
-	constDocs, err := extractConstDocs(filename, fset, f)
+func (c *messageCodeCollector) parseFile(filename string) error {
+	f, err := parser.ParseFile(c.fset, filename, nil, parser.ParseComments)
 	if err != nil {
-		return result, err
+		return fmt.Errorf("failed to parse file %s (%w)", filename, err)
 	}
+	c.collectFile(f)
+	return nil
+}
+
+// FindMessageCodeDeclarations returns the documented message code constants of a file parsed with comments, for
+// example by an analyzer checking the usage of the codes. Undocumented constants and other problems are not reported.
+func FindMessageCodeDeclarations(f *ast.File, filter MessageCodeFilter) []MessageCodeDeclaration {
+	collector := newMessageCodeCollector(filter)
+	collector.collectFile(f)
+	return collector.declared
+}
 
+// collectFile collects the message codes declared in a file parsed with comments using the collector's file set.
+func (c *messageCodeCollector) collectFile(f *ast.File) {
 	for _, decl := range f.Decls {
-		if genDecl, ok := decl.(*ast.GenDecl); ok {
-			if genDecl.Tok == token.CONST {
-				for _, spec := range genDecl.Specs {
-					if vSpec, ok := spec.(*ast.ValueSpec); ok {
-						for _, name := range vSpec.Names {
-							for _, val := range vSpec.Values {
-								d, ok := constDocs[name.Name]
-								if !ok || d == "" {
-									return result, fmt.Errorf("constant %s is not documented", name.Name)
-								}
-								basicVal, ok := val.(*ast.BasicLit)
-								if !ok {
-									return result, fmt.Errorf(
-										"the value of constant %s should be a basic string",
-										name.Name,
-									)
-								}
-								docParts := strings.Split(strings.TrimSpace(d), "\n")
-								resultingDocParts := []string{}
-								for _, part := range docParts {
-									if !strings.HasPrefix(part, "goland:") {
-										resultingDocParts = append(resultingDocParts, strings.TrimSpace(part))
-									}
-								}
-								result[strings.Trim(basicVal.Value, `"`)] = strings.Join(resultingDocParts, " ")
-							}
-						}
-					}
-				}
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			if vSpec, ok := spec.(*ast.ValueSpec); ok {
+				c.collectSpec(genDecl, vSpec)
 			}
 		}
 	}
+}
 
-	return result, nil
+func (c *messageCodeCollector) collectSpec(genDecl *ast.GenDecl, vSpec *ast.ValueSpec) {
+	docGroup := vSpec.Doc
+	if docGroup == nil && !genDecl.Lparen.IsValid() {
+		docGroup = genDecl.Doc
+	}
+	for i, name := range vSpec.Names {
+		if !c.filter.matches(name.Name, vSpec.Type) {
+			continue
+		}
+		position := c.fset.Position(name.Pos())
+		info := parseCodeDoc(docGroup)
+		if info.Description == "" {
+			c.problems = append(c.problems, fmt.Sprintf("%s: constant %s is not documented", position, name.Name))
+			continue
+		}
+		if info.Level != "" {
+			if _, err := info.Level.ToLevel(); err != nil {
+				c.problems = append(
+					c.problems,
+					fmt.Sprintf("%s: constant %s has an invalid level: %s", position, name.Name, info.Level),
+				)
+				continue
+			}
+		}
+		value, ok := constStringValue(vSpec, i)
+		if !ok {
+			c.problems = append(
+				c.problems,
+				fmt.Sprintf("%s: the value of constant %s should be a basic string", position, name.Name),
+			)
+			continue
+		}
+		info.Code = value
+		c.codes[value] = info.Description
+		c.infos[value] = info
+		c.declared = append(c.declared, MessageCodeDeclaration{Name: name.Name, Pos: name.Pos(), Info: info})
+	}
+}
+
+func (c *messageCodeCollector) result() (map[string]string, error) {
+	if len(c.problems) > 0 {
+		return c.codes, &MessageCodesError{Problems: c.problems}
+	}
+	return c.codes, nil
 }
 
-func extractConstDocs(
-	filename string,
-	fset *token.FileSet,
-	f *ast.File,
-) (map[string]string, error) {
-	p, err := doc.NewFromFiles(fset, []*ast.File{f}, "")
+func (c *messageCodeCollector) catalogue() (MessageCodeCatalogue, error) {
+	catalogue := MessageCodeCatalogue{Codes: make([]MessageCodeInfo, 0, len(c.infos))}
+	for _, info := range c.infos {
+		catalogue.Codes = append(catalogue.Codes, info)
+	}
+	catalogue = catalogue.sorted()
+	if len(c.problems) > 0 {
+		return catalogue, &MessageCodesError{Problems: c.problems}
+	}
+	return catalogue, nil
+}
+
+func constStringValue(vSpec *ast.ValueSpec, i int) (string, bool) {
+	if i >= len(vSpec.Values) {
+		return "", false
+	}
+	basicVal, ok := vSpec.Values[i].(*ast.BasicLit)
+	if !ok || basicVal.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(basicVal.Value)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse docs from file %s (%w)", filename, err)
+		return "", false
 	}
+	return value, true
+}
 
-	constDocs := map[string]string{}
+// codeDocTags are the structured tags recognised in the documentation of message codes.
+var codeDocTags = []string{"Level", "UserMessage", "URL", "Severity", "Resolution", "Since"}
 
-	for _, c := range p.Consts {
-		for _, name := range c.Names {
-			constDocs[name] = c.Doc
+// parseCodeDoc joins the documentation lines of a constant into the description, leaving out tool directives such as
+// goland:. A line starting with a tag such as "Resolution:" starts a tag section that continues until the next empty
+// line or tag.
+func parseCodeDoc(docGroup *ast.CommentGroup) MessageCodeInfo {
+	if docGroup == nil {
+		return MessageCodeInfo{}
+	}
+	var resultingDocParts []string
+	tags := map[string][]string{}
+	currentTag := ""
+	for _, part := range strings.Split(strings.TrimSpace(docGroup.Text()), "\n") {
+		part = strings.TrimSpace(part)
+		if tag, value, ok := parseCodeDocTag(part); ok {
+			currentTag = tag
+			tags[tag] = append(tags[tag], value)
+		} else if part == "" {
+			currentTag = ""
+		} else if strings.HasPrefix(part, "goland:") {
+			continue
+		} else if currentTag != "" {
+			tags[currentTag] = append(tags[currentTag], part)
+		} else {
+			resultingDocParts = append(resultingDocParts, part)
 		}
 	}
-	return constDocs, nil
+	return MessageCodeInfo{
+		Description:      strings.Join(resultingDocParts, " "),
+		Level:            LevelString(strings.Join(tags["Level"], " ")),
+		UserMessage:      strings.Join(tags["UserMessage"], " "),
+		DocumentationURL: strings.Join(tags["URL"], ""),
+		Severity:         strings.Join(tags["Severity"], " "),
+		Resolution:       strings.Join(tags["Resolution"], " "),
+		Since:            strings.Join(tags["Since"], " "),
+	}
 }
 
-// GenerateMessageCodeFiles generates the contents of the CODES.md file and returns them.
-func GenerateMessageCodesFile(filename string) (string, error) {
-	codes, err := GetMessageCodes(filename)
-	if err != nil {
-		return "", err
+func parseCodeDocTag(line string) (string, string, bool) {
+	for _, tag := range codeDocTags {
+		if strings.HasPrefix(line, tag+":") {
+			return tag, strings.TrimSpace(line[len(tag)+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// GetMessageCodes parses the specified go source file and returns a key-value mapping of message codes and their
+// associated description or an error. Constants are selected using DefaultMessageCodePrefixes.
+func GetMessageCodes(filename string) (map[string]string, error) {
+	return GetFileMessageCodes(filename, MessageCodeFilter{})
+}
+
+// GetFileMessageCodes returns the message codes from a single Go source file. All undocumented codes are reported in
+// a single MessageCodesError.
+func GetFileMessageCodes(filename string, filter MessageCodeFilter) (map[string]string, error) {
+	collector := newMessageCodeCollector(filter)
+	if err := collector.parseFile(filename); err != nil {
+		return map[string]string{}, err
+	}
+	return collector.result()
+}
+
+// GetFileMessageCodeCatalogue returns the message codes from a single Go source file as a catalogue including the
+// structured tags, such as Resolution:, found in the documentation.
+func GetFileMessageCodeCatalogue(filename string, filter MessageCodeFilter) (MessageCodeCatalogue, error) {
+	collector := newMessageCodeCollector(filter)
+	if err := collector.parseFile(filename); err != nil {
+		return MessageCodeCatalogue{}, err
 	}
+	return collector.catalogue()
+}
+
+// GetFilesMessageCodeCatalogue is identical to GetFileMessageCodeCatalogue, but collects the codes from several files,
+// for example all files of a package. All undocumented codes are reported in a single MessageCodesError, the valid
+// codes are still returned in this case. The codes package uses this function to extract the codes of whole packages
+// and modules.
+func GetFilesMessageCodeCatalogue(filenames []string, filter MessageCodeFilter) (MessageCodeCatalogue, error) {
+	collector := newMessageCodeCollector(filter)
+	for _, filename := range filenames {
+		if err := collector.parseFile(filename); err != nil {
+			return MessageCodeCatalogue{}, err
+		}
+	}
+	return collector.catalogue()
+}
+
+// GenerateMessageCodesDocument renders the contents of the CODES.md file from the message codes.
+func GenerateMessageCodesDocument(codes map[string]string) (string, error) {
 	tpl, err := template.New("CODES.md.tpl").Parse(messageFileTemplate)
 	if err != nil {
 		return "", fmt.Errorf("bug: failed to parse template (%w)", err)
@@ -111,12 +309,34 @@ func GenerateMessageCodesFile(filename string) (string, error) {
 	return wr.String(), nil
 }
 
+// GenerateMessageCodesFile generates the contents of the CODES.md file and returns them.
+func GenerateMessageCodesFile(filename string) (string, error) {
+	codes, err := GetMessageCodes(filename)
+	if err != nil {
+		return "", err
+	}
+	return GenerateMessageCodesDocument(codes)
+}
+
 // WriteMessageCodesFile generates and writes the CODES.md file
 func WriteMessageCodesFile(sourceFile string, destinationFile string) error {
 	data, err := GenerateMessageCodesFile(sourceFile)
 	if err != nil {
 		return err
 	}
+	return writeMessageCodesDocument(data, destinationFile)
+}
+
+// WriteMessageCodesDocument renders the message codes and writes them to the destination file.
+func WriteMessageCodesDocument(codes map[string]string, destinationFile string) error {
+	data, err := GenerateMessageCodesDocument(codes)
+	if err != nil {
+		return err
+	}
+	return writeMessageCodesDocument(data, destinationFile)
+}
+
+func writeMessageCodesDocument(data string, destinationFile string) error {
 	fh, err := os.Create(destinationFile)
 	if err != nil {
 		return fmt.Errorf("failed to open destination file %s (%w)", destinationFile, err)
@@ -0,0 +1,80 @@
+package log_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func TestGetMessageCodesReportsAllProblems(t *testing.T) {
+	codes, err := log.GetFileMessageCodes("testdata/codes/undocumented/codes.go", log.MessageCodeFilter{})
+	// The valid codes are still returned alongside the error.
+	assert.Equal(t, map[string]string{"DOCUMENTED": "This code is documented."}, codes)
+	var codesError *log.MessageCodesError
+	if !assert.True(t, errors.As(err, &codesError)) {
+		return
+	}
+	assert.Len(t, codesError.Problems, 4)
+	assert.Contains(t, codesError.Problems[0], "EFirstUndocumented is not documented")
+	assert.Contains(t, codesError.Problems[1], "ESecondUndocumented is not documented")
+	assert.Contains(t, codesError.Problems[2], "EComputed should be a basic string")
+	assert.Contains(t, codesError.Problems[3], "EInvalidLevel has an invalid level: fatal")
+}
+
+func TestGetMessageCodeCatalogueTags(t *testing.T) {
+	catalogue, err := log.GetFileMessageCodeCatalogue("testdata/codes/tags/codes.go", log.MessageCodeFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []log.MessageCodeInfo{
+		{
+			Code:             "BACKEND_FAILED",
+			Description:      "The connection to the backend failed.",
+			Level:            log.LevelErrorString,
+			UserMessage:      "The backend is not available.",
+			DocumentationURL: "https://example.com/codes/BACKEND_FAILED",
+			Severity:         "error",
+			Resolution:       "Check that the backend is running and reachable from ContainerSSH.",
+			Since:            "0.4.0",
+		},
+		{
+			Code:        "LOGIN_SUCCESSFUL",
+			Description: "The user logged in.",
+			Since:       "0.3.0",
+		},
+	}, catalogue.Codes)
+
+	// The tags are not part of the plain descriptions.
+	codes, err := log.GetFileMessageCodes("testdata/codes/tags/codes.go", log.MessageCodeFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, "The connection to the backend failed.", codes["BACKEND_FAILED"])
+}
+
+func TestGetFilesMessageCodeCatalogue(t *testing.T) {
+	catalogue, err := log.GetFilesMessageCodeCatalogue(
+		[]string{"testdata/codes/tags/codes.go", "testdata/codes/undocumented/codes.go"},
+		log.MessageCodeFilter{},
+	)
+	assert.Equal(t, []string{"BACKEND_FAILED", "DOCUMENTED", "LOGIN_SUCCESSFUL"}, []string{
+		catalogue.Codes[0].Code, catalogue.Codes[1].Code, catalogue.Codes[2].Code,
+	})
+	var codesError *log.MessageCodesError
+	if assert.True(t, errors.As(err, &codesError)) {
+		assert.Len(t, codesError.Problems, 4)
+	}
+}
+
+func TestGenerateMessageCodesDocumentWithTemplate(t *testing.T) {
+	catalogue, err := log.GetFileMessageCodeCatalogue("testdata/codes/tags/codes.go", log.MessageCodeFilter{})
+	assert.NoError(t, err)
+	templateText, err := os.ReadFile("testdata/codes/tags/CODES.md.tpl")
+	assert.NoError(t, err)
+	expected, err := os.ReadFile("testdata/codes/tags/CODES.md")
+	assert.NoError(t, err)
+
+	document, err := log.GenerateMessageCodesDocumentWithTemplate(catalogue, string(templateText))
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), document)
+}
@@ -0,0 +1,95 @@
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Validate returns an error if the label name is empty or contains characters other than A-Z, a-z, 0-9, - and _.
+func (name LabelName) Validate() error {
+	if name == "" {
+		return fmt.Errorf("empty label name")
+	}
+	for _, c := range name {
+		if !isValidLabelNameChar(c) {
+			return fmt.Errorf("invalid character %q in label name %s", c, name)
+		}
+	}
+	return nil
+}
+
+func isValidLabelNameChar(c rune) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_'
+}
+
+// ValidateLabelValue returns an error if the label value is not a string, integer, bool or float.
+func ValidateLabelValue(value LabelValue) error {
+	if value == nil {
+		return fmt.Errorf("label value is nil")
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.String, reflect.Bool:
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Float32, reflect.Float64:
+	default:
+		return fmt.Errorf("invalid label value type: %T", value)
+	}
+	return nil
+}
+
+// validateLabel applies the validation mode to a label. It returns false if the label should be dropped.
+func validateLabel(mode LabelValidation, name LabelName, value LabelValue) (LabelName, LabelValue, bool) {
+	if mode == LabelValidationNone || mode == "" {
+		return name, value, true
+	}
+	nameErr := name.Validate()
+	valueErr := ValidateLabelValue(value)
+	if nameErr == nil && valueErr == nil {
+		return name, value, true
+	}
+	switch mode {
+	case LabelValidationPanic:
+		if nameErr != nil {
+			panic(nameErr)
+		}
+		panic(fmt.Errorf("invalid value for label %s (%w)", name, valueErr))
+	case LabelValidationCoerce:
+		if nameErr != nil {
+			name = coerceLabelName(name)
+		}
+		if valueErr != nil {
+			value = fmt.Sprintf("%v", value)
+		}
+		return name, value, true
+	default:
+		return name, value, false
+	}
+}
+
+// coerceLabelName replaces invalid characters in the label name with underscores.
+func coerceLabelName(name LabelName) LabelName {
+	if name == "" {
+		return "_"
+	}
+	return LabelName(strings.Map(func(c rune) rune {
+		if isValidLabelNameChar(c) {
+			return c
+		}
+		return '_'
+	}, string(name)))
+}
+
+// newLabelDroppedMessage creates the warning logged when labels are dropped.
+func newLabelDroppedMessage(names []LabelName) Message {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("%q", name)
+	}
+	return NewMessage(
+		WLogLabelDropped,
+		"Dropped invalid log labels: %s",
+		strings.Join(quoted, ", "),
+	)
+}
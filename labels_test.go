@@ -0,0 +1,145 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func TestLabelNameValidation(t *testing.T) {
+	assert.NoError(t, log.LabelName("remote-Addr_2").Validate())
+	assert.Error(t, log.LabelName("").Validate())
+	assert.Error(t, log.LabelName("remote addr").Validate())
+	assert.Error(t, log.LabelName("remote.addr").Validate())
+}
+
+func TestLabelValueValidation(t *testing.T) {
+	for _, value := range []log.LabelValue{"foo", 5, uint16(5), -1.5, true, log.LevelInfoString, time.Second} {
+		assert.NoError(t, log.ValidateLabelValue(value))
+	}
+	for _, value := range []log.LabelValue{nil, []string{"a"}, map[string]int{}, struct{}{}} {
+		assert.Error(t, log.ValidateLabelValue(value))
+	}
+}
+
+func newLabelValidationTestLogger(buf *bytes.Buffer, mode log.LabelValidation) log.Logger {
+	return log.MustNewLogger(log.Config{
+		Level:           log.LevelDebug,
+		Format:          log.FormatLJSON,
+		Destination:     log.DestinationStdout,
+		Stdout:          buf,
+		LabelValidation: mode,
+	})
+}
+
+func TestMessageLabelNotValidated(t *testing.T) {
+	msg := log.NewMessage(log.MTest, "test").Label("invalid name", []string{"foo"})
+	assert.Equal(t, log.Labels{"invalid name": []string{"foo"}}, msg.Labels())
+}
+
+func TestMessageLabelValidationPanic(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLabelValidationTestLogger(&buf, log.LabelValidationPanic)
+	assert.Panics(t, func() {
+		logger.Info(log.NewMessage(log.MTest, "test").Label("invalid name", "foo"))
+	})
+	assert.Panics(t, func() {
+		logger.Info(log.NewMessage(log.MTest, "test").Label("name", []string{"foo"}))
+	})
+	assert.NotPanics(t, func() {
+		logger.Info(log.NewMessage(log.MTest, "test").Label("name", "foo"))
+	})
+}
+
+func TestMessageLabelValidationCoerce(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLabelValidationTestLogger(&buf, log.LabelValidationCoerce)
+	msg := log.NewMessage(log.MTest, "test").
+		Label("remote addr", "192.0.2.1").
+		Label("list", []string{"a", "b"})
+	logger.Info(msg)
+
+	line := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, map[string]interface{}{
+		"remote_addr": "192.0.2.1",
+		"list":        "[a b]",
+	}, line["details"])
+	assert.Equal(t, log.Labels{
+		"remote addr": "192.0.2.1",
+		"list":        []string{"a", "b"},
+	}, msg.Labels())
+}
+
+func TestMessageLabelValidationDrop(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLabelValidationTestLogger(&buf, log.LabelValidationDrop)
+	logger.Info(log.NewMessage(log.MTest, "test").Label("invalid name", "foo").Label("valid", "bar"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	first := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, map[string]interface{}{"valid": "bar"}, first["details"])
+	second := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	assert.Equal(t, log.WLogLabelDropped, second["code"])
+	assert.Equal(t, "warning", second["level"])
+}
+
+func TestWrappedMessageLabelValidation(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLabelValidationTestLogger(&buf, log.LabelValidationCoerce)
+	cause := fmt.Errorf("cause")
+	msg := log.Wrap(cause, log.MTest, "test").Label("remote addr", "192.0.2.1")
+	logger.Info(msg)
+
+	assert.Contains(t, buf.String(), `"remote_addr":"192.0.2.1"`)
+	assert.True(t, errors.Is(msg, cause))
+}
+
+func TestLoggerLabelValidation(t *testing.T) {
+	var buf bytes.Buffer
+	config := log.Config{
+		Level:           log.LevelDebug,
+		Format:          log.FormatLJSON,
+		Destination:     log.DestinationStdout,
+		Stdout:          &buf,
+		LabelValidation: log.LabelValidationPanic,
+	}
+	logger := log.MustNewLogger(config)
+	assert.Panics(t, func() {
+		logger.WithLabel("invalid name", "foo")
+	})
+
+	config.LabelValidation = log.LabelValidationDrop
+	logger = log.MustNewLogger(config).WithLabel("invalid name", "foo")
+	assert.Contains(t, buf.String(), log.WLogLabelDropped)
+	buf.Reset()
+	logger.Info(log.NewMessage(log.MTest, "test"))
+	assert.NotContains(t, buf.String(), "invalid name")
+
+	config.LabelValidation = log.LabelValidationNone
+	assert.Panics(t, func() {
+		log.MustNewLogger(config).WithLabel("", "foo")
+	})
+}
+
+func TestFormattedLogLabelValidation(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLabelValidationTestLogger(&buf, log.LabelValidationCoerce).WithLabel("remote addr", "192.0.2.1")
+	logger.Logf("test %d", 1)
+
+	line := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "test 1", line["message"])
+	assert.Equal(t, map[string]interface{}{"remote_addr": "192.0.2.1"}, line["details"])
+}
@@ -14,6 +14,7 @@ type Logger interface {
 	// WithLevel returns a copy of the logger for a specified log level. Panics if the log level provided is invalid.
 	WithLevel(level Level) Logger
 	// WithLabel returns a logger with an added label (e.g. username, IP, etc.) Panics if the label name is empty.
+	// Invalid labels are handled according to the LabelValidation setting, which also applies to message labels.
 	WithLabel(labelName LabelName, labelValue LabelValue) Logger
 
 	// Debug logs a message at the debug level.
@@ -55,29 +55,46 @@ func (f *loggerFactory) Make(config Config) (Logger, error) {
 		return nil, err
 	}
 
+	if err := config.Timestamp.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := config.LabelValidation.Validate(); err != nil {
+		return nil, err
+	}
+
 	var writer Writer
 	var err error = nil
 	switch config.Destination {
 	case DestinationFile:
-		writer, err = newFileWriter(config.File, config.Format)
+		writer, err = newFileWriter(config)
 	case DestinationStdout:
 		var stdout io.Writer = os.Stdout
 		if config.Stdout != nil {
 			stdout = config.Stdout
 		}
-		writer, err = newStdoutWriter(stdout, config.Format)
+		writer, err = newStdoutWriter(stdout, config)
 	case DestinationSyslog:
-		writer, err = newSyslogWriter(config.Syslog, config.Format)
+		writer, err = newSyslogWriter(config)
 	case DestinationTest:
 		writer = newGoTest(config.T)
+	case DestinationOTLP:
+		writer, err = newOTLPWriter(config.OTLP, newTimeSource(config))
+	case DestinationFluentForward:
+		writer, err = newFluentForwardWriter(config.FluentForward, newTimeSource(config))
+	case DestinationSplunkHEC:
+		writer, err = newSplunkHECWriter(config.SplunkHEC, newTimeSource(config))
+	case DestinationWebhook:
+		writer, err = newWebhookWriter(config.Webhook, newTimeSource(config))
 	}
 	if err != nil {
 		return nil, err
 	}
 
 	return &logger{
-		level:  config.Level,
-		labels: map[LabelName]LabelValue{},
-		writer: writer,
+		level:           config.Level,
+		labels:          map[LabelName]LabelValue{},
+		writer:          writer,
+		labelValidation: config.LabelValidation,
 	}, nil
 }
@@ -1,9 +1,15 @@
 package log
 
+import (
+	"fmt"
+	"sort"
+)
+
 type logger struct {
-	level  Level
-	labels Labels
-	writer Writer
+	level           Level
+	labels          Labels
+	writer          Writer
+	labelValidation LabelValidation
 }
 
 func (pipeline *logger) Close() error {
@@ -16,22 +22,32 @@ func (pipeline *logger) Rotate() error {
 
 func (pipeline *logger) WithLevel(level Level) Logger {
 	return &logger{
-		level:  level,
-		labels: pipeline.labels,
-		writer: pipeline.writer,
+		level:           level,
+		labels:          pipeline.labels,
+		writer:          pipeline.writer,
+		labelValidation: pipeline.labelValidation,
 	}
 }
 
 func (pipeline *logger) WithLabel(labelName LabelName, labelValue LabelValue) Logger {
+	if labelName == "" {
+		panic(fmt.Errorf("empty label name"))
+	}
+	labelName, labelValue, ok := validateLabel(pipeline.labelValidation, labelName, labelValue)
+	if !ok {
+		pipeline.warnDroppedLabels([]LabelName{labelName})
+		return pipeline
+	}
 	newLabels := make(Labels, len(pipeline.labels))
 	for k, v := range pipeline.labels {
 		newLabels[k] = v
 	}
 	newLabels[labelName] = labelValue
 	return &logger{
-		level:  pipeline.level,
-		labels: newLabels,
-		writer: pipeline.writer,
+		level:           pipeline.level,
+		labels:          newLabels,
+		writer:          pipeline.writer,
+		labelValidation: pipeline.labelValidation,
 	}
 }
 
@@ -60,32 +76,77 @@ func (pipeline *logger) write(level Level, message ...interface{}) {
 			msg = NewMessage(EUnknownError, "%v", message)
 		}
 
-		for label, value := range pipeline.labels {
-			msg = msg.Label(label, value)
-		}
-
-		if err := pipeline.writer.Write(level, msg); err != nil {
-			panic(err)
-		}
+		pipeline.writeMessage(level, msg)
 	}
 }
 
 func (pipeline *logger) writef(level Level, format string, args ...interface{}) {
 	if pipeline.level >= level {
-		var msg Message
+		pipeline.writeMessage(level, NewMessage(EUnknownError, format, args...))
+	}
+}
 
-		msg = NewMessage(EUnknownError, format, args...)
+// writeMessage adds the logger labels to the message, applies the LabelValidation setting and writes the message. The
+// formatted and unformatted log methods both use it, so labels are validated the same way regardless of the method.
+func (pipeline *logger) writeMessage(level Level, msg Message) {
+	msg = pipeline.addLabels(msg)
+	msg, dropped := pipeline.validateLabels(msg)
 
-		for label, value := range pipeline.labels {
-			msg = msg.Label(label, value)
-		}
+	if err := pipeline.writer.Write(level, msg); err != nil {
+		panic(err)
+	}
+	if len(dropped) > 0 {
+		pipeline.warnDroppedLabels(dropped)
+	}
+}
 
-		if err := pipeline.writer.Write(level, msg); err != nil {
+// warnDroppedLabels logs a warning about dropped labels. The logger labels are not added to avoid dropping them again.
+func (pipeline *logger) warnDroppedLabels(names []LabelName) {
+	if pipeline.level >= LevelWarning {
+		if err := pipeline.writer.Write(LevelWarning, newLabelDroppedMessage(names)); err != nil {
 			panic(err)
 		}
 	}
 }
 
+// addLabels returns a copy of the message with the logger labels merged in a single pass. Logger labels take
+// precedence over message labels with the same name.
+func (pipeline *logger) addLabels(msg Message) Message {
+	if len(pipeline.labels) == 0 {
+		return msg
+	}
+	labels := make(Labels, len(msg.Labels())+len(pipeline.labels))
+	for name, value := range msg.Labels() {
+		labels[name] = value
+	}
+	for name, value := range pipeline.labels {
+		labels[name] = value
+	}
+	return withLabels(msg, labels)
+}
+
+// validateLabels applies the LabelValidation setting to the labels of the message. It returns the message with the
+// coerced or dropped labels replaced and the sorted names of the dropped labels.
+func (pipeline *logger) validateLabels(msg Message) (Message, []LabelName) {
+	if pipeline.labelValidation == LabelValidationNone || pipeline.labelValidation == "" {
+		return msg, nil
+	}
+	labels := make(Labels, len(msg.Labels()))
+	var dropped []LabelName
+	for name, value := range msg.Labels() {
+		newName, newValue, ok := validateLabel(pipeline.labelValidation, name, value)
+		if !ok {
+			dropped = append(dropped, name)
+			continue
+		}
+		labels[newName] = newValue
+	}
+	sort.Slice(dropped, func(i, j int) bool {
+		return dropped[i] < dropped[j]
+	})
+	return withLabels(msg, labels), dropped
+}
+
 func (pipeline *logger) wrapError(err error) Message {
 	return Wrap(
 		err,
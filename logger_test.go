@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/containerssh/structutils"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/containerssh/log"
@@ -71,3 +75,36 @@ func testLevel(t *testing.T, logLevel log.Level, writeLogLevel log.Level) {
 		assert.Equal(t, "test", data["message"])
 	}
 }
+
+// newDestinationTestConfig returns the default configuration with the specified destination.
+func newDestinationTestConfig(destination log.Destination) log.Config {
+	config := log.Config{}
+	structutils.Defaults(&config)
+	config.Destination = destination
+	return config
+}
+
+// startHTTPTestServer starts a server that passes each request and its body to the returned channels. The first
+// requests are answered with the specified status codes and not recorded.
+func startHTTPTestServer(t *testing.T, statusCodes ...int) (*httptest.Server, chan *http.Request, chan []byte) {
+	requests := make(chan *http.Request, 10)
+	bodies := make(chan []byte, 10)
+	// The handler runs on a new goroutine for each request, so the status codes are handed out through a channel.
+	responses := make(chan int, len(statusCodes))
+	for _, statusCode := range statusCodes {
+		responses <- statusCode
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		select {
+		case statusCode := <-responses:
+			w.WriteHeader(statusCode)
+			return
+		default:
+		}
+		requests <- r
+		bodies <- body
+	}))
+	return server, requests, bodies
+}
@@ -0,0 +1,30 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+var mergedMessageFileTemplate = `# Message / error codes
+
+| Code | Module | Explanation |
+|------|--------|-------------|
+{{range . -}}
+| ` + "`{{ .Code }}`" + ` | {{ .Owner }} | {{ .Description }} |
+{{end}}
+`
+
+// GenerateMergedMessageCodesDocument renders a CODES.md file from a merged catalogue including the owning module of
+// each code.
+func GenerateMergedMessageCodesDocument(catalogue MessageCodeCatalogue) (string, error) {
+	tpl, err := template.New("CODES.md.tpl").Parse(mergedMessageFileTemplate)
+	if err != nil {
+		return "", fmt.Errorf("bug: failed to parse template (%w)", err)
+	}
+	wr := &bytes.Buffer{}
+	if err := tpl.Execute(wr, catalogue.Codes); err != nil {
+		return "", fmt.Errorf("failed to render codes template (%w)", err)
+	}
+	return wr.String(), nil
+}
@@ -0,0 +1,22 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func TestGenerateMergedMessageCodesDocument(t *testing.T) {
+	document, err := log.GenerateMergedMessageCodesDocument(log.MessageCodeCatalogue{
+		Codes: []log.MessageCodeInfo{
+			{Code: "CONTAINER_STARTED", Description: "The backend container was started.", Owner: "example.com/backend"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "# Message / error codes\n\n"+
+		"| Code | Module | Explanation |\n"+
+		"|------|--------|-------------|\n"+
+		"| `CONTAINER_STARTED` | example.com/backend | The backend container was started. |\n\n", document)
+}
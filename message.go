@@ -38,7 +38,8 @@ func WrapUser(Cause error, Code string, User string, Explanation string, Args ..
 	}
 }
 
-// NewMessage creates an internal error with only the explanation for the administrator inserted.
+// NewMessage creates an internal error with only the explanation for the administrator inserted. The user message is
+// taken from the message code registry, or "Internal Error" if the code has no registered user message.
 //
 // - Code is an error code allowing an administrator to identify the error that happened.
 // - Explanation is the explanation string to the system administrator. This is an fmt.Sprintf-compatible string
@@ -47,14 +48,15 @@ func WrapUser(Cause error, Code string, User string, Explanation string, Args ..
 func NewMessage(Code string, Explanation string, Args ...interface{}) Message {
 	return UserMessage(
 		Code,
-		"Internal Error",
+		getDefaultUserMessage(Code),
 		Explanation,
 		Args...,
 	)
 }
 
 // Wrap creates a wrapped error with a specific Code and Explanation string. The wrapping method will automatically
-//      append the error message in brackets.
+//      append the error message in brackets. The user message is taken from the message code registry like with
+//      NewMessage.
 //
 // - Cause is the original error that can be accessed with the Unwrap method.
 // - Code is an error code allowing an administrator to identify the error that happened.
@@ -86,9 +88,10 @@ type Message interface {
 	UserMessage() string
 	// Explanation is the text explanation for the system administrator.
 	Explanation() string
-	// Labels are a set of extra labels for the message containing information.
+	// Labels are a set of extra labels for the message containing information. The returned map must not be modified.
 	Labels() Labels
-	// Label adds a label to the message.
+	// Label returns a copy of the message with the label added. The original message is not modified. Labels are not
+	// validated here, the logger applies its LabelValidation setting when the message is written.
 	Label(name LabelName, value LabelValue) Message
 }
 
@@ -130,15 +133,31 @@ func (m *message) String() string {
 	return m.userMessage
 }
 
+// Label returns a copy of the message with the label added. The original message is not modified, so messages can be
+// shared between goroutines.
 func (m *message) Label(name LabelName, value LabelValue) Message {
-	m.labels[name] = value
-	return m
+	labels := make(Labels, len(m.labels)+1)
+	for k, v := range m.labels {
+		labels[k] = v
+	}
+	labels[name] = value
+	return m.withLabels(labels)
 }
 
 func (m *message) Error() string {
 	return m.explanation
 }
 
+// withLabels returns a copy of the message with the labels replaced. The labels are not copied.
+func (m *message) withLabels(labels Labels) Message {
+	return &message{
+		code:        m.code,
+		userMessage: m.userMessage,
+		explanation: m.explanation,
+		labels:      labels,
+	}
+}
+
 //endregion
 
 //region Wrapping message implementation
@@ -152,4 +171,65 @@ func (w wrappingMessage) Unwrap() error {
 	return w.cause
 }
 
+// Label returns a copy of the wrapping message with the label added.
+func (w wrappingMessage) Label(name LabelName, value LabelValue) Message {
+	return &wrappingMessage{
+		Message: w.Message.Label(name, value),
+		cause:   w.cause,
+	}
+}
+
+//endregion
+
+//region Relabelled message implementation
+
+// withLabels returns a copy of the message with the labels replaced. The labels are not copied. Messages implemented
+// outside this package are wrapped so their other methods stay available through the embedded interface.
+func withLabels(msg Message, labels Labels) Message {
+	switch m := msg.(type) {
+	case *message:
+		return m.withLabels(labels)
+	case *wrappingMessage:
+		return &wrappingMessage{
+			Message: withLabels(m.Message, labels),
+			cause:   m.cause,
+		}
+	case *relabelledMessage:
+		return &relabelledMessage{
+			Message: m.Message,
+			labels:  labels,
+		}
+	default:
+		return &relabelledMessage{
+			Message: msg,
+			labels:  labels,
+		}
+	}
+}
+
+// relabelledMessage overrides the labels of a message implemented outside this package.
+type relabelledMessage struct {
+	Message
+	labels Labels
+}
+
+func (r *relabelledMessage) Labels() Labels {
+	return r.labels
+}
+
+// Unwrap returns the original message so errors.As can find its type.
+func (r *relabelledMessage) Unwrap() error {
+	return r.Message
+}
+
+// Label returns a copy of the message with the label added.
+func (r *relabelledMessage) Label(name LabelName, value LabelValue) Message {
+	labels := make(Labels, len(r.labels)+1)
+	for k, v := range r.labels {
+		labels[k] = v
+	}
+	labels[name] = value
+	return withLabels(r, labels)
+}
+
 //endregion
@@ -0,0 +1,92 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func TestMessageLabelReturnsCopy(t *testing.T) {
+	original := log.NewMessage(log.MTest, "test").Label("a", 1)
+	labelled := original.Label("b", 2)
+
+	assert.Equal(t, log.Labels{"a": 1}, original.Labels())
+	assert.Equal(t, log.Labels{"a": 1, "b": 2}, labelled.Labels())
+}
+
+func TestWrappingMessageLabelKeepsCause(t *testing.T) {
+	cause := errors.New("connection refused")
+	labelled := log.Wrap(cause, log.MTest, "failed to connect").Label("host", "example.com")
+
+	assert.True(t, errors.Is(labelled, cause))
+	assert.Equal(t, log.Labels{"host": "example.com"}, labelled.Labels())
+}
+
+// TestSharedMessageConcurrentLogging logs the same message through differently labelled loggers from multiple
+// goroutines. Run with -race to detect concurrent map access.
+func TestSharedMessageConcurrentLogging(t *testing.T) {
+	const workers = 10
+	const messagesPerWorker = 100
+
+	shared := log.Wrap(errors.New("shared cause"), log.MTest, "Shared message").Label("shared", true)
+	buffers := make([]*bytes.Buffer, workers)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		buffers[i] = &bytes.Buffer{}
+		logger := log.MustNewLogger(log.Config{
+			Level:       log.LevelDebug,
+			Format:      log.FormatLJSON,
+			Destination: log.DestinationStdout,
+			Stdout:      buffers[i],
+		}).WithLabel("worker", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < messagesPerWorker; j++ {
+				logger.Info(shared)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, log.Labels{"shared": true}, shared.Labels())
+	for i, buf := range buffers {
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Len(t, lines, messagesPerWorker)
+		for _, line := range lines {
+			data := map[string]interface{}{}
+			assert.NoError(t, json.Unmarshal([]byte(line), &data))
+			assert.Equal(
+				t,
+				map[string]interface{}{"shared": true, "worker": float64(i)},
+				data["details"],
+				fmt.Sprintf("worker %d received foreign labels", i),
+			)
+		}
+	}
+}
+
+func TestLoggerLabelsOverrideMessageLabels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.MustNewLogger(log.Config{
+		Level:       log.LevelDebug,
+		Format:      log.FormatLJSON,
+		Destination: log.DestinationStdout,
+		Stdout:      &buf,
+	}).WithLabel("a", "logger").WithLabel("b", "logger")
+	msg := log.NewMessage(log.MTest, "test").Label("a", "message").Label("c", "message")
+	logger.Info(msg)
+
+	data := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &data))
+	assert.Equal(t, map[string]interface{}{"a": "logger", "b": "logger", "c": "message"}, data["details"])
+	assert.Equal(t, log.Labels{"a": "message", "c": "message"}, msg.Labels())
+}
@@ -0,0 +1,351 @@
+package log
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// This file contains a minimal MessagePack encoder and decoder covering the types used in log messages, so the
+// package does not need an external dependency.
+
+const (
+	// msgpackExtEventTime is the extension type Fluentd uses for nanosecond precision timestamps.
+	msgpackExtEventTime = 0
+	// msgpackExtTimestamp is the standard MessagePack timestamp extension type.
+	msgpackExtTimestamp int8 = -1
+)
+
+type msgpackEncoder struct {
+	buf []byte
+}
+
+func (e *msgpackEncoder) bytes() []byte {
+	return e.buf
+}
+
+func (e *msgpackEncoder) writeNil() {
+	e.buf = append(e.buf, 0xc0)
+}
+
+func (e *msgpackEncoder) writeBool(v bool) {
+	if v {
+		e.buf = append(e.buf, 0xc3)
+	} else {
+		e.buf = append(e.buf, 0xc2)
+	}
+}
+
+func (e *msgpackEncoder) writeInt(v int64) {
+	switch {
+	case v >= 0:
+		e.writeUint(uint64(v))
+	case v >= -32:
+		e.buf = append(e.buf, byte(v))
+	case v >= math.MinInt8:
+		e.buf = append(e.buf, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		e.buf = append(e.buf, 0xd1)
+		e.buf = appendUint16(e.buf, uint16(v))
+	case v >= math.MinInt32:
+		e.buf = append(e.buf, 0xd2)
+		e.buf = appendUint32(e.buf, uint32(v))
+	default:
+		e.buf = append(e.buf, 0xd3)
+		e.buf = appendUint64(e.buf, uint64(v))
+	}
+}
+
+func (e *msgpackEncoder) writeUint(v uint64) {
+	switch {
+	case v <= 0x7f:
+		e.buf = append(e.buf, byte(v))
+	case v <= math.MaxUint8:
+		e.buf = append(e.buf, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		e.buf = append(e.buf, 0xcd)
+		e.buf = appendUint16(e.buf, uint16(v))
+	case v <= math.MaxUint32:
+		e.buf = append(e.buf, 0xce)
+		e.buf = appendUint32(e.buf, uint32(v))
+	default:
+		e.buf = append(e.buf, 0xcf)
+		e.buf = appendUint64(e.buf, v)
+	}
+}
+
+func (e *msgpackEncoder) writeFloat(v float64) {
+	e.buf = append(e.buf, 0xcb)
+	e.buf = appendUint64(e.buf, math.Float64bits(v))
+}
+
+func (e *msgpackEncoder) writeString(v string) {
+	l := len(v)
+	switch {
+	case l <= 31:
+		e.buf = append(e.buf, 0xa0|byte(l))
+	case l <= math.MaxUint8:
+		e.buf = append(e.buf, 0xd9, byte(l))
+	case l <= math.MaxUint16:
+		e.buf = append(e.buf, 0xda)
+		e.buf = appendUint16(e.buf, uint16(l))
+	default:
+		e.buf = append(e.buf, 0xdb)
+		e.buf = appendUint32(e.buf, uint32(l))
+	}
+	e.buf = append(e.buf, v...)
+}
+
+func (e *msgpackEncoder) writeBinary(v []byte) {
+	l := len(v)
+	switch {
+	case l <= math.MaxUint8:
+		e.buf = append(e.buf, 0xc4, byte(l))
+	case l <= math.MaxUint16:
+		e.buf = append(e.buf, 0xc5)
+		e.buf = appendUint16(e.buf, uint16(l))
+	default:
+		e.buf = append(e.buf, 0xc6)
+		e.buf = appendUint32(e.buf, uint32(l))
+	}
+	e.buf = append(e.buf, v...)
+}
+
+func (e *msgpackEncoder) writeArrayHeader(l int) {
+	switch {
+	case l <= 15:
+		e.buf = append(e.buf, 0x90|byte(l))
+	case l <= math.MaxUint16:
+		e.buf = append(e.buf, 0xdc)
+		e.buf = appendUint16(e.buf, uint16(l))
+	default:
+		e.buf = append(e.buf, 0xdd)
+		e.buf = appendUint32(e.buf, uint32(l))
+	}
+}
+
+func (e *msgpackEncoder) writeMapHeader(l int) {
+	switch {
+	case l <= 15:
+		e.buf = append(e.buf, 0x80|byte(l))
+	case l <= math.MaxUint16:
+		e.buf = append(e.buf, 0xde)
+		e.buf = appendUint16(e.buf, uint16(l))
+	default:
+		e.buf = append(e.buf, 0xdf)
+		e.buf = appendUint32(e.buf, uint32(l))
+	}
+}
+
+// writeEventTime writes a timestamp in the Fluentd EventTime extension format.
+func (e *msgpackEncoder) writeEventTime(t time.Time) {
+	e.buf = append(e.buf, 0xd7, msgpackExtEventTime)
+	e.buf = appendUint32(e.buf, uint32(t.Unix()))
+	e.buf = appendUint32(e.buf, uint32(t.Nanosecond()))
+}
+
+// writeValue writes an arbitrary label value. Types without a MessagePack equivalent are written as strings.
+func (e *msgpackEncoder) writeValue(value interface{}) {
+	writeEncodedValue(e, value)
+}
+
+// writeTimestamp writes a timestamp using the standard MessagePack timestamp extension in the 96 bit format.
+func (e *msgpackEncoder) writeTimestamp(t time.Time) {
+	e.buf = append(e.buf, 0xc7, 12, 0xff) // msgpackExtTimestamp
+	e.buf = appendUint32(e.buf, uint32(t.Nanosecond()))
+	e.buf = appendUint64(e.buf, uint64(t.Unix()))
+}
+
+func appendUint16(buf []byte, v uint16) []byte {
+	return append(buf, byte(v>>8), byte(v))
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	return append(buf, byte(v>>56), byte(v>>48), byte(v>>40), byte(v>>32), byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// msgpackDecoder reads MessagePack values from a stream. Maps are decoded into map[string]interface{}, arrays into
+// []interface{}, integers into int64 or uint64 and the EventTime and timestamp extensions into time.Time.
+type msgpackDecoder struct {
+	r *bufio.Reader
+}
+
+func newMsgpackDecoder(r io.Reader) *msgpackDecoder {
+	return &msgpackDecoder{r: bufio.NewReader(r)}
+}
+
+func (d *msgpackDecoder) decode() (interface{}, error) {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case b <= 0x7f:
+		return int64(b), nil
+	case b >= 0xe0:
+		return int64(int8(b)), nil
+	case b&0xe0 == 0xa0:
+		return d.readString(int(b & 0x1f))
+	case b&0xf0 == 0x90:
+		return d.readArray(int(b & 0x0f))
+	case b&0xf0 == 0x80:
+		return d.readMap(int(b & 0x0f))
+	}
+	return d.decodeTyped(b)
+}
+
+func (d *msgpackDecoder) decodeTyped(b byte) (interface{}, error) {
+	switch b {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xca:
+		v, err := d.readUint(4)
+		return float64(math.Float32frombits(uint32(v))), err
+	case 0xcb:
+		v, err := d.readUint(8)
+		return math.Float64frombits(v), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		v, err := d.readUint(1 << (b - 0xcc))
+		if err != nil || v > math.MaxInt64 {
+			return v, err
+		}
+		return int64(v), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		return d.readInt(1 << (b - 0xd0))
+	case 0xc4, 0xc5, 0xc6:
+		return d.readSized(1<<(b-0xc4), d.readBinary)
+	case 0xd9, 0xda, 0xdb:
+		return d.readSized(1<<(b-0xd9), func(l int) (interface{}, error) { return d.readString(l) })
+	case 0xdc, 0xdd:
+		return d.readSized(2<<(b-0xdc), d.readArray)
+	case 0xde, 0xdf:
+		return d.readSized(2<<(b-0xde), d.readMap)
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.readExt(1 << (b - 0xd4))
+	case 0xc7, 0xc8, 0xc9:
+		l, err := d.readUint(1 << (b - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		if l > maxBinaryRecordSize {
+			return nil, fmt.Errorf("MessagePack item too long: %d", l)
+		}
+		return d.readExt(int(l))
+	}
+	return nil, fmt.Errorf("invalid MessagePack type byte: 0x%x", b)
+}
+
+func (d *msgpackDecoder) readSized(sizeBytes int, read func(l int) (interface{}, error)) (interface{}, error) {
+	l, err := d.readUint(sizeBytes)
+	if err != nil {
+		return nil, err
+	}
+	if l > maxBinaryRecordSize {
+		return nil, fmt.Errorf("MessagePack item too long: %d", l)
+	}
+	return read(int(l))
+}
+
+func (d *msgpackDecoder) readUint(n int) (uint64, error) {
+	data := make([]byte, 8)
+	if _, err := io.ReadFull(d.r, data[8-n:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+func (d *msgpackDecoder) readInt(n int) (int64, error) {
+	v, err := d.readUint(n)
+	if err != nil {
+		return 0, err
+	}
+	shift := uint(64 - 8*n)
+	return int64(v<<shift) >> shift, nil
+}
+
+func (d *msgpackDecoder) readBinary(l int) (interface{}, error) {
+	data := make([]byte, l)
+	if _, err := io.ReadFull(d.r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (d *msgpackDecoder) readString(l int) (string, error) {
+	data, err := d.readBinary(l)
+	if err != nil {
+		return "", err
+	}
+	return string(data.([]byte)), nil
+}
+
+// readArray decodes an array of l items. The slice grows as the items are read since the length is not trusted.
+func (d *msgpackDecoder) readArray(l int) (interface{}, error) {
+	result := []interface{}{}
+	for i := 0; i < l; i++ {
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// readMap decodes a map of l entries. The map grows as the entries are read since the length is not trusted.
+func (d *msgpackDecoder) readMap(l int) (interface{}, error) {
+	result := map[string]interface{}{}
+	for i := 0; i < l; i++ {
+		k, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		result[fmt.Sprintf("%v", k)] = v
+	}
+	return result, nil
+}
+
+func (d *msgpackDecoder) readExt(l int) (interface{}, error) {
+	extType, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	data, err := d.readBinary(l)
+	if err != nil {
+		return nil, err
+	}
+	raw := data.([]byte)
+	switch {
+	case int8(extType) == msgpackExtEventTime && len(raw) == 8:
+		return time.Unix(
+			int64(binary.BigEndian.Uint32(raw[:4])),
+			int64(binary.BigEndian.Uint32(raw[4:])),
+		), nil
+	case int8(extType) == msgpackExtTimestamp && len(raw) == 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(raw)), 0), nil
+	case int8(extType) == msgpackExtTimestamp && len(raw) == 8:
+		v := binary.BigEndian.Uint64(raw)
+		return time.Unix(int64(v&0x3ffffffff), int64(v>>34)), nil
+	case int8(extType) == msgpackExtTimestamp && len(raw) == 12:
+		return time.Unix(
+			int64(binary.BigEndian.Uint64(raw[4:])),
+			int64(binary.BigEndian.Uint32(raw[:4])),
+		), nil
+	}
+	return raw, nil
+}
@@ -0,0 +1,132 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// MessageCodeInfo describes a message code registered at runtime.
+type MessageCodeInfo struct {
+	// Code is the message code, e.g. LOG_WRITE_FAILED.
+	Code string `json:"code" yaml:"code"`
+	// Description explains the meaning of the code to the administrator.
+	Description string `json:"description" yaml:"description"`
+	// Level is the default level messages with this code are logged at by LogMessage. If empty, the error level is
+	// used. Level is the only field affecting how a message is logged. It is filled from the Level: documentation tag.
+	Level LevelString `json:"level,omitempty" yaml:"level,omitempty"`
+	// UserMessage is the default user-facing message used by NewMessage and Wrap. If empty, "Internal Error" is used.
+	// It is filled from the UserMessage: documentation tag.
+	UserMessage string `json:"userMessage,omitempty" yaml:"userMessage,omitempty"`
+	// DocumentationURL points to further documentation about the code. It is filled from the URL: documentation tag.
+	DocumentationURL string `json:"documentationUrl,omitempty" yaml:"documentationUrl,omitempty"`
+	// Owner is the module defining the code. Only set in catalogues merged from multiple modules.
+	Owner string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	// Severity is the severity from the Severity: tag of the code documentation. It is free-form documentation, e.g.
+	// for SIEM rules, and is never used to choose the log level. Use Level for that.
+	Severity string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	// Resolution describes how to resolve the problem, taken from the Resolution: tag of the code documentation.
+	Resolution string `json:"resolution,omitempty" yaml:"resolution,omitempty"`
+	// Since is the version the code was introduced in, taken from the Since: tag of the code documentation.
+	Since string `json:"since,omitempty" yaml:"since,omitempty"`
+}
+
+// defaultUserMessage is the user-facing message for codes without a registered user message.
+const defaultUserMessage = "Internal Error"
+
+var messageCodeRegistry = struct {
+	lock  *sync.RWMutex
+	codes map[string]MessageCodeInfo
+}{
+	lock:  &sync.RWMutex{},
+	codes: map[string]MessageCodeInfo{},
+}
+
+// RegisterMessageCode registers a message code so its metadata can be looked up at runtime. Registering the same
+// code again replaces the previous entry. This is typically called from an init function generated by
+// containerssh-generate-codes.
+func RegisterMessageCode(info MessageCodeInfo) {
+	messageCodeRegistry.lock.Lock()
+	defer messageCodeRegistry.lock.Unlock()
+	messageCodeRegistry.codes[info.Code] = info
+}
+
+// GetMessageCodeInfo returns the registered information for a message code.
+func GetMessageCodeInfo(code string) (MessageCodeInfo, bool) {
+	messageCodeRegistry.lock.RLock()
+	defer messageCodeRegistry.lock.RUnlock()
+	info, ok := messageCodeRegistry.codes[code]
+	return info, ok
+}
+
+// ListMessageCodes returns all message codes registered in the binary, sorted by code.
+func ListMessageCodes() []MessageCodeInfo {
+	messageCodeRegistry.lock.RLock()
+	defer messageCodeRegistry.lock.RUnlock()
+	result := make([]MessageCodeInfo, 0, len(messageCodeRegistry.codes))
+	for _, info := range messageCodeRegistry.codes {
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Code < result[j].Code
+	})
+	return result
+}
+
+// MessageCodesHandler returns an HTTP handler for debug endpoints that responds with all registered message codes
+// as a JSON catalogue.
+func MessageCodesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(MessageCodeCatalogue{Codes: ListMessageCodes()})
+	})
+}
+
+// getDefaultUserMessage returns the registered user message for the code, or "Internal Error".
+func getDefaultUserMessage(code string) string {
+	if info, ok := GetMessageCodeInfo(code); ok && info.UserMessage != "" {
+		return info.UserMessage
+	}
+	return defaultUserMessage
+}
+
+// LogMessage logs the message at the default level registered for its code. If the code is not registered or has no
+// valid default level, the message is logged at the error level.
+func LogMessage(logger Logger, message Message) {
+	level := LevelError
+	if info, ok := GetMessageCodeInfo(message.Code()); ok && info.Level != "" {
+		if registeredLevel, err := info.Level.ToLevel(); err == nil {
+			level = registeredLevel
+		}
+	}
+	// The level is either LevelError or was validated by ToLevel, so LogAtLevel cannot fail.
+	_ = LogAtLevel(logger, level, message)
+}
+
+// LogAtLevel logs the message at the specified level, e.g. when replaying messages read back using a Decoder. It returns
+// an error without logging the message if the level is invalid.
+func LogAtLevel(logger Logger, level Level, message Message) error {
+	switch level {
+	case LevelDebug:
+		logger.Debug(message)
+	case LevelInfo:
+		logger.Info(message)
+	case LevelNotice:
+		logger.Notice(message)
+	case LevelWarning:
+		logger.Warning(message)
+	case LevelError:
+		logger.Error(message)
+	case LevelCritical:
+		logger.Critical(message)
+	case LevelAlert:
+		logger.Alert(message)
+	case LevelEmergency:
+		logger.Emergency(message)
+	default:
+		return fmt.Errorf("invalid log level (%d)", level)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func TestRegistryDefaultUserMessage(t *testing.T) {
+	log.RegisterMessageCode(log.MessageCodeInfo{
+		Code:        "TEST_REGISTRY_USER_MESSAGE",
+		Description: "Test code with a user message.",
+		UserMessage: "Please try again later.",
+	})
+
+	assert.Equal(t, "Please try again later.", log.NewMessage("TEST_REGISTRY_USER_MESSAGE", "test").UserMessage())
+	assert.Equal(
+		t,
+		"Please try again later.",
+		log.Wrap(errors.New("cause"), "TEST_REGISTRY_USER_MESSAGE", "test").UserMessage(),
+	)
+	assert.Equal(t, "Internal Error", log.NewMessage("TEST_REGISTRY_UNREGISTERED", "test").UserMessage())
+}
+
+func TestRegistryLogMessage(t *testing.T) {
+	log.RegisterMessageCode(log.MessageCodeInfo{
+		Code:        "TEST_REGISTRY_LEVEL",
+		Description: "Test code with a default level.",
+		Level:       log.LevelWarningString,
+	})
+
+	var buf bytes.Buffer
+	logger := log.MustNewLogger(log.Config{
+		Level:       log.LevelDebug,
+		Format:      log.FormatLJSON,
+		Destination: log.DestinationStdout,
+		Stdout:      &buf,
+	})
+
+	log.LogMessage(logger, log.NewMessage("TEST_REGISTRY_LEVEL", "test"))
+	data := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &data))
+	assert.Equal(t, "warning", data["level"])
+
+	buf.Reset()
+	log.LogMessage(logger, log.NewMessage("TEST_REGISTRY_UNREGISTERED", "test"))
+	data = map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &data))
+	assert.Equal(t, "error", data["level"])
+}
+
+func TestLogAtLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.MustNewLogger(log.Config{
+		Level:       log.LevelDebug,
+		Format:      log.FormatLJSON,
+		Destination: log.DestinationStdout,
+		Stdout:      &buf,
+	})
+
+	assert.NoError(t, log.LogAtLevel(logger, log.LevelEmergency, log.NewMessage(log.MTest, "test")))
+	data := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &data))
+	assert.Equal(t, "emerg", data["level"])
+
+	buf.Reset()
+	assert.Error(t, log.LogAtLevel(logger, log.Level(42), log.NewMessage(log.MTest, "test")))
+	assert.Equal(t, 0, buf.Len())
+}
+
+func TestRegistryListing(t *testing.T) {
+	log.RegisterMessageCode(log.MessageCodeInfo{
+		Code:             "TEST_REGISTRY_LISTING",
+		Description:      "Test code for the listing.",
+		DocumentationURL: "https://example.com/codes/TEST_REGISTRY_LISTING",
+	})
+
+	codes := log.ListMessageCodes()
+	for i := 1; i < len(codes); i++ {
+		assert.True(t, codes[i-1].Code < codes[i].Code)
+	}
+
+	recorder := httptest.NewRecorder()
+	log.MessageCodesHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/codes", nil))
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	catalogue := log.MessageCodeCatalogue{}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &catalogue))
+	assert.Contains(t, catalogue.Codes, log.MessageCodeInfo{
+		Code:             "TEST_REGISTRY_LISTING",
+		Description:      "Test code for the listing.",
+		DocumentationURL: "https://example.com/codes/TEST_REGISTRY_LISTING",
+	})
+	info, ok := log.GetMessageCodeInfo(log.ELogWriteFailed)
+	assert.True(t, ok)
+	assert.Contains(t, catalogue.Codes, info)
+}
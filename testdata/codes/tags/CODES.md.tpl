@@ -0,0 +1,15 @@
+# Message codes
+{{ range .Codes }}
+## {{ .Code }}
+
+{{ .Description }}
+{{ if .Severity }}
+**Severity:** {{ .Severity }}
+{{ end -}}
+{{ if .Resolution }}
+**Resolution:** {{ .Resolution }}
+{{ end -}}
+{{ if .Since }}
+*Introduced in {{ .Since }}.*
+{{ end -}}
+{{ end -}}
@@ -0,0 +1,17 @@
+package tags
+
+// The connection to the backend failed.
+//
+// Severity: error
+// Resolution: Check that the backend is running
+// and reachable from ContainerSSH.
+// Since: 0.4.0
+// Level: error
+// UserMessage: The backend is not available.
+// URL: https://example.com/codes/BACKEND_FAILED
+const EBackendFailed = "BACKEND_FAILED"
+
+// The user logged in.
+// Since: 0.3.0
+//goland:noinspection GoUnusedConst
+const MLoginSuccessful = "LOGIN_SUCCESSFUL"
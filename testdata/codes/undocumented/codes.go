@@ -0,0 +1,15 @@
+package undocumented
+
+const EFirstUndocumented = "FIRST_UNDOCUMENTED"
+
+// This code is documented.
+const EDocumented = "DOCUMENTED"
+
+const ESecondUndocumented = "SECOND_UNDOCUMENTED"
+
+// This code does not have a string value.
+const EComputed = "COMPUTED" + "_VALUE"
+
+// This code has an invalid default level.
+// Level: fatal
+const EInvalidLevel = "INVALID_LEVEL"
@@ -0,0 +1,123 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// newTimeSource creates the source of timestamps for log messages from the configuration.
+func newTimeSource(config Config) *timeSource {
+	now := config.Clock
+	if now == nil {
+		now = time.Now
+	}
+	return &timeSource{
+		clock:  now,
+		config: config.Timestamp,
+	}
+}
+
+// timeSource provides the current time and formats it according to the timestamp configuration.
+type timeSource struct {
+	clock  func() time.Time
+	config TimestampConfig
+}
+
+// now returns the current time, converted to UTC if configured.
+func (t *timeSource) now() time.Time {
+	now := t.clock()
+	if t.config.UTC {
+		now = now.UTC()
+	}
+	return now
+}
+
+// format formats the timestamp as a string.
+func (t *timeSource) format(timestamp time.Time) string {
+	switch t.config.Format {
+	case TimestampFormatRFC3339Nano:
+		return timestamp.Format(time.RFC3339Nano)
+	case TimestampFormatUnix:
+		return fmt.Sprintf("%d", timestamp.Unix())
+	case TimestampFormatUnixMilli:
+		return fmt.Sprintf("%d", timestamp.UnixNano()/int64(time.Millisecond))
+	case TimestampFormatUnixNano:
+		return fmt.Sprintf("%d", timestamp.UnixNano())
+	case TimestampFormatCustom:
+		return timestamp.Format(t.config.Layout)
+	default:
+		return timestamp.Format(time.RFC3339)
+	}
+}
+
+// value returns the timestamp for structured formats. The Unix formats are returned as numbers, all others as strings.
+func (t *timeSource) value(timestamp time.Time) interface{} {
+	switch t.config.Format {
+	case TimestampFormatUnix:
+		return timestamp.Unix()
+	case TimestampFormatUnixMilli:
+		return timestamp.UnixNano() / int64(time.Millisecond)
+	case TimestampFormatUnixNano:
+		return timestamp.UnixNano()
+	default:
+		return t.format(timestamp)
+	}
+}
+
+// parse converts a timestamp read back from a structured format into a time. It is the reverse of value. Numbers in
+// a non-Unix format are interpreted as seconds, milliseconds or nanoseconds depending on their magnitude. Layouts
+// without a time zone are interpreted in the zone the timestamps were written in.
+func (t *timeSource) parse(value interface{}) (time.Time, error) {
+	var timestamp time.Time
+	switch v := value.(type) {
+	case string:
+		layout := time.RFC3339Nano
+		if t.config.Format == TimestampFormatCustom {
+			layout = t.config.Layout
+		}
+		location := time.Local
+		if t.config.UTC {
+			location = time.UTC
+		}
+		parsed, err := time.ParseInLocation(layout, v, location)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp %s (%w)", v, err)
+		}
+		timestamp = parsed
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp %s (%w)", v, err)
+		}
+		timestamp = t.fromUnix(n)
+	default:
+		return time.Time{}, fmt.Errorf("missing or invalid timestamp")
+	}
+	if t.config.UTC {
+		timestamp = timestamp.UTC()
+	}
+	return timestamp, nil
+}
+
+func (t *timeSource) fromUnix(n int64) time.Time {
+	format := t.config.Format
+	if format != TimestampFormatUnix && format != TimestampFormatUnixMilli && format != TimestampFormatUnixNano {
+		switch {
+		case n < 1e11:
+			format = TimestampFormatUnix
+		case n < 1e14:
+			format = TimestampFormatUnixMilli
+		default:
+			format = TimestampFormatUnixNano
+		}
+	}
+	switch format {
+	case TimestampFormatUnix:
+		return time.Unix(n, 0)
+	case TimestampFormatUnixMilli:
+		return time.Unix(0, n*int64(time.Millisecond))
+	default:
+		return time.Unix(0, n)
+	}
+}
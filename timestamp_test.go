@@ -0,0 +1,106 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func TestTimestampFormats(t *testing.T) {
+	zone := time.FixedZone("test", 2*60*60)
+	now := time.Date(2021, 3, 4, 5, 6, 7, 890123456, zone)
+	for format, expected := range map[log.TimestampFormat]interface{}{
+		log.TimestampFormatRFC3339:     "2021-03-04T05:06:07+02:00",
+		log.TimestampFormatRFC3339Nano: "2021-03-04T05:06:07.890123456+02:00",
+		log.TimestampFormatUnix:        float64(1614827167),
+		log.TimestampFormatUnixMilli:   float64(1614827167890),
+		log.TimestampFormatUnixNano:    float64(1614827167890123456),
+	} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := log.MustNewLogger(log.Config{
+				Level:       log.LevelDebug,
+				Format:      log.FormatLJSON,
+				Destination: log.DestinationStdout,
+				Stdout:      &buf,
+				Timestamp:   log.TimestampConfig{Format: format},
+				Clock: func() time.Time {
+					return now
+				},
+			})
+			logger.Info(log.NewMessage(log.MTest, "Hello world!"))
+			data := map[string]interface{}{}
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &data))
+			assert.Equal(t, expected, data["timestamp"])
+		})
+	}
+}
+
+func TestTimestampCustomLayoutUTC(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.MustNewLogger(log.Config{
+		Level:       log.LevelDebug,
+		Format:      log.FormatText,
+		Destination: log.DestinationStdout,
+		Stdout:      &buf,
+		Timestamp: log.TimestampConfig{
+			Format: log.TimestampFormatCustom,
+			Layout: "2006-01-02 15:04:05 MST",
+			UTC:    true,
+		},
+		Clock: func() time.Time {
+			return time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("test", 2*60*60))
+		},
+	})
+	logger.Info(log.NewMessage(log.MTest, "Hello world!"))
+	assert.True(t, strings.HasPrefix(buf.String(), "2021-03-04 03:06:07 UTC\tinfo\tHello world!"))
+}
+
+func TestTimestampCustomWithoutLayout(t *testing.T) {
+	config := log.Config{
+		Level:       log.LevelDebug,
+		Format:      log.FormatText,
+		Destination: log.DestinationStdout,
+		Timestamp:   log.TimestampConfig{Format: log.TimestampFormatCustom},
+	}
+	assert.Error(t, config.Validate())
+}
+
+func TestTimestampCustomLayoutWithoutZoneRoundTrip(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("test", 2*60*60)
+	defer func() {
+		time.Local = local
+	}()
+
+	for _, utc := range []bool{false, true} {
+		var buf bytes.Buffer
+		now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.Local)
+		config := log.Config{
+			Level:       log.LevelDebug,
+			Format:      log.FormatLJSON,
+			Destination: log.DestinationStdout,
+			Stdout:      &buf,
+			Timestamp: log.TimestampConfig{
+				Format: log.TimestampFormatCustom,
+				Layout: "2006-01-02 15:04:05",
+				UTC:    utc,
+			},
+			Clock: func() time.Time {
+				return now
+			},
+		}
+		logger := log.MustNewLogger(config)
+		logger.Info(log.NewMessage(log.MTest, "Hello world!"))
+
+		entry, err := log.NewLJSONDecoder(&buf, config).Decode()
+		assert.NoError(t, err)
+		assert.True(t, now.Equal(entry.Time), "utc=%t: %s is not %s", utc, entry.Time, now)
+	}
+}
@@ -0,0 +1,46 @@
+package log
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// traceParentLabel is the label holding a W3C trace context traceparent header value.
+const traceParentLabel LabelName = "traceparent"
+
+// extractTraceContext reads the trace and span IDs from the specified labels or from a W3C traceparent label and
+// removes the labels it used from the passed map.
+func extractTraceContext(labels Labels, traceIDLabel LabelName, spanIDLabel LabelName) (traceID []byte, spanID []byte) {
+	if traceParent, ok := labels[traceParentLabel]; ok {
+		parts := strings.Split(fmt.Sprintf("%v", traceParent), "-")
+		if len(parts) == 4 {
+			traceID = decodeTraceID(parts[1], 16)
+			spanID = decodeTraceID(parts[2], 8)
+			if traceID != nil && spanID != nil {
+				delete(labels, traceParentLabel)
+			}
+		}
+	}
+	if value, ok := labels[traceIDLabel]; ok {
+		if id := decodeTraceID(fmt.Sprintf("%v", value), 16); id != nil {
+			traceID = id
+			delete(labels, traceIDLabel)
+		}
+	}
+	if value, ok := labels[spanIDLabel]; ok {
+		if id := decodeTraceID(fmt.Sprintf("%v", value), 8); id != nil {
+			spanID = id
+			delete(labels, spanIDLabel)
+		}
+	}
+	return traceID, spanID
+}
+
+func decodeTraceID(value string, length int) []byte {
+	id, err := hex.DecodeString(value)
+	if err != nil || len(id) != length {
+		return nil
+	}
+	return id
+}
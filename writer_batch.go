@@ -0,0 +1,187 @@
+package log
+
+import (
+	"sync"
+	"time"
+)
+
+// batchEntry is a snapshot of a log message taken when it is written. The labels are copied since messages implemented
+// outside this package may change them while the entry waits to be sent.
+type batchEntry struct {
+	time        time.Time
+	level       Level
+	code        string
+	userMessage string
+	explanation string
+	labels      Labels
+}
+
+func newBatchEntry(now time.Time, level Level, message Message) batchEntry {
+	labels := make(Labels, len(message.Labels()))
+	for name, value := range message.Labels() {
+		labels[name] = value
+	}
+	return batchEntry{
+		time:        now,
+		level:       level,
+		code:        message.Code(),
+		userMessage: message.UserMessage(),
+		explanation: message.Explanation(),
+		labels:      labels,
+	}
+}
+
+// newBatchWriter creates a writer that collects messages and queues them for the send function when the batch is full
+// or the batch interval has elapsed. The send function runs on a separate goroutine so slow destinations never block
+// Write. When the queue is full the batch is dropped. Failed and dropped messages are counted and reported by Close.
+func newBatchWriter(
+	config BatchConfig,
+	timeSource *timeSource,
+	send func(entries []batchEntry) error,
+) *batchWriter {
+	w := &batchWriter{
+		lock:       &sync.Mutex{},
+		closeOnce:  &sync.Once{},
+		config:     config,
+		timeSource: timeSource,
+		send:       send,
+		queue:      make(chan []batchEntry, config.QueueSize),
+		done:       make(chan struct{}),
+		closed:     make(chan struct{}),
+		sent:       make(chan struct{}),
+	}
+	go w.run()
+	go w.sendQueued()
+	return w
+}
+
+type batchWriter struct {
+	lock       *sync.Mutex
+	closeOnce  *sync.Once
+	config     BatchConfig
+	timeSource *timeSource
+	send       func(entries []batchEntry) error
+	entries    []batchEntry
+	stopped    bool
+	queue      chan []batchEntry
+	done       chan struct{}
+	closed     chan struct{}
+	sent       chan struct{}
+	closeError error
+
+	// statsLock guards the counters below. It is separate from lock so the send goroutine never waits for Write.
+	statsLock sync.Mutex
+	failed    int
+	dropped   int
+	sendError error
+}
+
+func (b *batchWriter) Write(level Level, message Message) error {
+	if _, err := level.Name(); err != nil {
+		return err
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.stopped {
+		b.countDropped(1)
+		return nil
+	}
+	b.entries = append(b.entries, newBatchEntry(b.timeSource.now(), level, message))
+	if len(b.entries) >= b.config.Size {
+		b.enqueue()
+	}
+	return nil
+}
+
+func (b *batchWriter) Rotate() error {
+	return nil
+}
+
+// Close sends the pending messages, waits for the queue to drain and returns an error if any message was lost.
+func (b *batchWriter) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.done)
+		<-b.closed
+		b.lock.Lock()
+		entries := b.entries
+		b.entries = nil
+		b.stopped = true
+		b.lock.Unlock()
+		if len(entries) > 0 {
+			b.queue <- entries
+		}
+		close(b.queue)
+		<-b.sent
+		b.closeError = b.lostError()
+	})
+	return b.closeError
+}
+
+func (b *batchWriter) run() {
+	defer close(b.closed)
+	ticker := time.NewTicker(b.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			b.lock.Lock()
+			b.enqueue()
+			b.lock.Unlock()
+		case <-b.done:
+			return
+		}
+	}
+}
+
+func (b *batchWriter) sendQueued() {
+	defer close(b.sent)
+	for entries := range b.queue {
+		if err := b.send(entries); err != nil {
+			b.statsLock.Lock()
+			b.failed += len(entries)
+			if b.sendError == nil {
+				b.sendError = err
+			}
+			b.statsLock.Unlock()
+		}
+	}
+}
+
+// enqueue passes the pending entries to the send goroutine without blocking. The caller must hold the lock.
+func (b *batchWriter) enqueue() {
+	if len(b.entries) == 0 {
+		return
+	}
+	select {
+	case b.queue <- b.entries:
+	default:
+		b.countDropped(len(b.entries))
+	}
+	b.entries = nil
+}
+
+func (b *batchWriter) countDropped(count int) {
+	b.statsLock.Lock()
+	defer b.statsLock.Unlock()
+	b.dropped += count
+}
+
+// lostError returns an error describing the messages that failed to send or were dropped, or nil if there were none.
+func (b *batchWriter) lostError() error {
+	b.statsLock.Lock()
+	defer b.statsLock.Unlock()
+	switch {
+	case b.sendError != nil:
+		return Wrap(
+			b.sendError,
+			ELogWriteFailed,
+			"%d log messages could not be sent and %d were dropped",
+			b.failed,
+			b.dropped,
+		)
+	case b.dropped > 0:
+		return NewMessage(ELogWriteFailed, "%d log messages were dropped because the send queue was full", b.dropped)
+	default:
+		return nil
+	}
+}
@@ -5,15 +5,20 @@ import (
 	"sync"
 )
 
-func newFileWriter(filename string, format Format) (Writer, error) {
+func newFileWriter(config Config) (Writer, error) {
 	lock := &sync.Mutex{}
-	fh, err := openLogFile(filename)
+	fh, err := openLogFile(config.File)
 	if err != nil {
 		return nil, err
 	}
+	fileHandleWriter, err := newFileHandleWriter(fh, config, lock)
+	if err != nil {
+		_ = fh.Close()
+		return nil, err
+	}
 	return &fileWriter{
-		fileHandleWriter: newFileHandleWriter(fh, format, lock),
-		filename:         filename,
+		fileHandleWriter: fileHandleWriter,
+		filename:         config.File,
 		lock:             lock,
 		fh:               fh,
 	}, nil
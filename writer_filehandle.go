@@ -1,26 +1,54 @@
 package log
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 )
 
-func newFileHandleWriter(fh io.Writer, format Format, lock *sync.Mutex) *fileHandleWriter {
-	return &fileHandleWriter{
-		fh:     fh,
-		lock:   lock,
-		format: format,
+func newFileHandleWriter(fh io.Writer, config Config, lock *sync.Mutex) (*fileHandleWriter, error) {
+	var tpl *template.Template
+	if config.Format == FormatTemplate {
+		var err error
+		if tpl, err = parseLineTemplate(config.Template); err != nil {
+			return nil, err
+		}
+	}
+	var ljson *ljsonFormatter
+	if config.Format == FormatLJSON {
+		var err error
+		if ljson, err = newLJSONFormatter(config.LJSON); err != nil {
+			return nil, err
+		}
+	}
+	var console *consoleFormatter
+	if config.Format == FormatConsole {
+		console = newConsoleFormatter(fh)
 	}
+	return &fileHandleWriter{
+		fh:         fh,
+		lock:       lock,
+		format:     config.Format,
+		config:     config,
+		template:   tpl,
+		ljson:      ljson,
+		console:    console,
+		timeSource: newTimeSource(config),
+	}, nil
 }
 
 type fileHandleWriter struct {
-	lock   *sync.Mutex
-	fh     io.Writer
-	format Format
+	lock       *sync.Mutex
+	fh         io.Writer
+	format     Format
+	config     Config
+	template   *template.Template
+	ljson      *ljsonFormatter
+	console    *consoleFormatter
+	timeSource *timeSource
 }
 
 func (f *fileHandleWriter) Write(level Level, message Message) error {
@@ -34,7 +62,10 @@ func (f *fileHandleWriter) Write(level Level, message Message) error {
 	if err != nil {
 		return Wrap(err, ELogWriteFailed, "failed to write log message")
 	}
-	if _, err := f.fh.Write(append(line, '\n')); err != nil {
+	if !f.format.isBinary() {
+		line = append(line, '\n')
+	}
+	if _, err := f.fh.Write(line); err != nil {
 		return Wrap(err, ELogWriteFailed, "failed to write log message")
 	}
 	return nil
@@ -49,65 +80,54 @@ func (f *fileHandleWriter) Close() error {
 }
 
 func (f *fileHandleWriter) createLine(levelString LevelString, message Message) (line []byte, err error) {
+	now := f.timeSource.now()
 	switch f.format {
 	case FormatLJSON:
-		line, err = f.createLineLJSON(levelString, message)
+		line, err = f.ljson.createLine(f.timeSource.value(now), levelString, message)
 		if err != nil {
 			return nil, err
 		}
 	case FormatText:
-		line = f.createLineText(levelString, message)
+		line = f.createLineText(now, levelString, message)
+	case FormatECS:
+		line, err = createLineECS(now, levelString, message)
+		if err != nil {
+			return nil, err
+		}
+	case FormatGCP:
+		line, err = createLineGCP(now, levelString, message, f.config.GCP)
+		if err != nil {
+			return nil, err
+		}
+	case FormatCEF:
+		line = createLineCEF(now, levelString, message, f.config.Device)
+	case FormatLEEF:
+		line = createLineLEEF(now, levelString, message, f.config.Device)
+	case FormatTemplate:
+		line, err = createLineTemplate(f.template, now, levelString, message)
+		if err != nil {
+			return nil, err
+		}
+	case FormatCBOR, FormatMsgpack:
+		line = createRecordBinary(f.format, now, levelString, message)
+	case FormatConsole:
+		line = f.console.createLine(f.timeSource.format(now), levelString, message)
 	default:
 		return nil, fmt.Errorf("log format not supported: %s", f.format)
 	}
 	return line, nil
 }
 
-func (f *fileHandleWriter) createLineText(levelString LevelString, message Message) []byte {
+func (f *fileHandleWriter) createLineText(now time.Time, levelString LevelString, message Message) []byte {
 	msg := message.Explanation()
-	var labels []string
-	for labelName, labelValue := range message.Labels() {
-		labels = append(labels, fmt.Sprintf("%s=%s", labelName, labelValue))
-	}
-	if len(labels) > 0 {
+	if labels := formatTextLabels(message.Labels()); len(labels) > 0 {
 		msg += fmt.Sprintf(" (%s)", strings.Join(labels, " "))
 	}
 	line := []byte(fmt.Sprintf(
 		"%s\t%s\t%s\n",
-		time.Now().Format(time.RFC3339),
+		f.timeSource.format(now),
 		levelString,
 		msg,
 	))
 	return line
 }
-
-func (f *fileHandleWriter) createLineLJSON(levelString LevelString, message Message) (
-	[]byte,
-	error,
-) {
-	details := map[string]interface{}{}
-	for label, value := range message.Labels() {
-		details[string(label)] = value
-	}
-	line, err := json.Marshal(
-		jsonLine{
-			Time:    time.Now().Format(time.RFC3339),
-			Code:    message.Code(),
-			Level:   string(levelString),
-			Message: message.Explanation(),
-			Details: details,
-		},
-	)
-	if err != nil {
-		return nil, err
-	}
-	return line, nil
-}
-
-type jsonLine struct {
-	Time    string                 `json:"timestamp"`
-	Level   string                 `json:"level"`
-	Code    string                 `json:"code"`
-	Message string                 `json:"message"`
-	Details map[string]interface{} `json:"details"`
-}
@@ -0,0 +1,231 @@
+package log
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fluentForwardMessageQueueSize is the number of messages queued in message mode, where the batch configuration is
+// not used.
+const fluentForwardMessageQueueSize = 1000
+
+func newFluentForwardWriter(config FluentForwardConfig, timeSource *timeSource) (Writer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	batchConfig := config.Batch
+	if config.Mode == FluentForwardModeMessage {
+		// Message mode sends every message immediately and ignores the batch configuration, which may be empty.
+		batchConfig = BatchConfig{
+			Size:      1,
+			Interval:  time.Second,
+			QueueSize: fluentForwardMessageQueueSize,
+		}
+	}
+	w := &fluentForwardWriter{
+		config:         config,
+		connectionLock: &sync.Mutex{},
+	}
+	w.batchWriter = newBatchWriter(batchConfig, timeSource, w.send)
+	return w, nil
+}
+
+// fluentForwardWriter inherits the batching behavior from batchWriter and sends the messages using the Fluentd
+// forward protocol. The connection is opened lazily and reopened after errors.
+type fluentForwardWriter struct {
+	*batchWriter
+
+	config         FluentForwardConfig
+	connectionLock *sync.Mutex
+	connection     net.Conn
+}
+
+func (f *fluentForwardWriter) Rotate() error {
+	f.connectionLock.Lock()
+	defer f.connectionLock.Unlock()
+	return f.disconnect()
+}
+
+func (f *fluentForwardWriter) Close() error {
+	err := f.batchWriter.Close()
+	f.connectionLock.Lock()
+	defer f.connectionLock.Unlock()
+	if closeErr := f.disconnect(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (f *fluentForwardWriter) send(entries []batchEntry) error {
+	var requests [][]byte
+	var chunks []string
+	switch f.config.Mode {
+	case FluentForwardModeMessage:
+		for _, entry := range entries {
+			request, chunk, err := f.createMessageRequest(entry)
+			if err != nil {
+				return err
+			}
+			requests = append(requests, request)
+			chunks = append(chunks, chunk)
+		}
+	default:
+		request, chunk, err := f.createForwardRequest(entries)
+		if err != nil {
+			return err
+		}
+		requests = append(requests, request)
+		chunks = append(chunks, chunk)
+	}
+
+	f.connectionLock.Lock()
+	defer f.connectionLock.Unlock()
+	for i, request := range requests {
+		if err := f.sendRequest(request, chunks[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createMessageRequest creates a request in the Message mode: [tag, time, record, option]
+func (f *fluentForwardWriter) createMessageRequest(entry batchEntry) ([]byte, string, error) {
+	option, chunk, err := f.createOption(1)
+	if err != nil {
+		return nil, "", err
+	}
+	e := &msgpackEncoder{}
+	e.writeArrayHeader(4)
+	e.writeString(f.config.Tag)
+	e.writeEventTime(entry.time)
+	writeFluentForwardRecord(e, entry)
+	e.writeValue(option)
+	return e.bytes(), chunk, nil
+}
+
+// createForwardRequest creates a request in the Forward or PackedForward mode: [tag, entries, option]
+func (f *fluentForwardWriter) createForwardRequest(entries []batchEntry) ([]byte, string, error) {
+	option, chunk, err := f.createOption(len(entries))
+	if err != nil {
+		return nil, "", err
+	}
+	entryEncoder := &msgpackEncoder{}
+	for _, entry := range entries {
+		entryEncoder.writeArrayHeader(2)
+		entryEncoder.writeEventTime(entry.time)
+		writeFluentForwardRecord(entryEncoder, entry)
+	}
+
+	e := &msgpackEncoder{}
+	e.writeArrayHeader(3)
+	e.writeString(f.config.Tag)
+	if f.config.Mode == FluentForwardModePackedForward {
+		e.writeBinary(entryEncoder.bytes())
+	} else {
+		e.writeArrayHeader(len(entries))
+		e.buf = append(e.buf, entryEncoder.bytes()...)
+	}
+	e.writeValue(option)
+	return e.bytes(), chunk, nil
+}
+
+func (f *fluentForwardWriter) createOption(size int) (map[string]interface{}, string, error) {
+	option := map[string]interface{}{
+		"size": size,
+	}
+	if !f.config.RequireAck {
+		return option, "", nil
+	}
+	chunkID := make([]byte, 16)
+	if _, err := rand.Read(chunkID); err != nil {
+		return nil, "", Wrap(err, ELogWriteFailed, "failed to generate fluent forward chunk ID")
+	}
+	chunk := base64.StdEncoding.EncodeToString(chunkID)
+	option["chunk"] = chunk
+	return option, chunk, nil
+}
+
+// writeFluentForwardRecord writes the record map. Labels are added as record fields, but cannot override the level,
+// code and message fields.
+func writeFluentForwardRecord(e *msgpackEncoder, entry batchEntry) {
+	record := map[string]interface{}{}
+	for name, value := range entry.labels {
+		record[string(name)] = value
+	}
+	record["level"] = string(entry.level.MustName())
+	record["code"] = entry.code
+	record["message"] = entry.explanation
+	e.writeValue(record)
+}
+
+// sendRequest writes a request to the server, reconnecting once if the existing connection fails. The caller must
+// hold the connection lock.
+func (f *fluentForwardWriter) sendRequest(request []byte, chunk string) error {
+	err := f.trySendRequest(request, chunk)
+	if err == nil {
+		return nil
+	}
+	_ = f.disconnect()
+	if err = f.trySendRequest(request, chunk); err != nil {
+		_ = f.disconnect()
+		return Wrap(err, ELogWriteFailed, "failed to send log message to fluent forward server %s", f.config.Address)
+	}
+	return nil
+}
+
+func (f *fluentForwardWriter) trySendRequest(request []byte, chunk string) error {
+	if err := f.connect(); err != nil {
+		return err
+	}
+	if err := f.connection.SetDeadline(time.Now().Add(f.config.Timeout)); err != nil {
+		return err
+	}
+	if _, err := f.connection.Write(request); err != nil {
+		return err
+	}
+	if chunk == "" {
+		return nil
+	}
+	response, err := newMsgpackDecoder(f.connection).decode()
+	if err != nil {
+		return fmt.Errorf("failed to read acknowledgement (%w)", err)
+	}
+	responseMap, ok := response.(map[string]interface{})
+	if !ok || responseMap["ack"] != chunk {
+		return fmt.Errorf("invalid acknowledgement received for chunk %s", chunk)
+	}
+	return nil
+}
+
+func (f *fluentForwardWriter) connect() error {
+	if f.connection != nil {
+		return nil
+	}
+	network := "tcp"
+	if strings.HasPrefix(f.config.Address, "/") {
+		network = "unix"
+	}
+	connection, err := net.DialTimeout(network, f.config.Address, f.config.Timeout)
+	if err != nil {
+		return err
+	}
+	f.connection = connection
+	return nil
+}
+
+func (f *fluentForwardWriter) disconnect() error {
+	if f.connection == nil {
+		return nil
+	}
+	err := f.connection.Close()
+	f.connection = nil
+	if err != nil {
+		return Wrap(err, ELogRotateFailed, "failed to close fluent forward connection")
+	}
+	return nil
+}
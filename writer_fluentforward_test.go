@@ -0,0 +1,146 @@
+package log_test
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+// startFluentForwardServer starts a forward protocol server that decodes each request, optionally acknowledges it, and
+// passes it to the returned channel.
+func startFluentForwardServer(t *testing.T) (net.Listener, chan []interface{}) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := make(chan []interface{}, 10)
+	go func() {
+		for {
+			connection, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFluentForward(connection, requests)
+		}
+	}()
+	return listener, requests
+}
+
+func serveFluentForward(connection net.Conn, requests chan []interface{}) {
+	defer func() {
+		_ = connection.Close()
+	}()
+	reader := bufio.NewReader(connection)
+	for {
+		request, err := log.DecodeMsgpack(reader)
+		if err != nil {
+			return
+		}
+		parts := request.([]interface{})
+		option := parts[len(parts)-1].(map[string]interface{})
+		if chunk, ok := option["chunk"]; ok {
+			if _, err := connection.Write(append([]byte{0x81, 0xa3, 'a', 'c', 'k', 0xb8}, chunk.(string)...)); err != nil {
+				return
+			}
+		}
+		requests <- parts
+	}
+}
+
+func TestFluentForwardMessageMode(t *testing.T) {
+	listener, requests := startFluentForwardServer(t)
+	defer func() {
+		_ = listener.Close()
+	}()
+
+	config := newDestinationTestConfig(log.DestinationFluentForward)
+	config.FluentForward.Address = listener.Addr().String()
+	config.FluentForward.Tag = "test.tag"
+	config.FluentForward.RequireAck = true
+	logger := log.MustNewLogger(config)
+	logger.Error(
+		log.NewMessage(log.MTest, "Hello world!").
+			Label("username", "foo").
+			Label("nested", map[string]interface{}{"a": 1}),
+	)
+	assert.NoError(t, logger.Close())
+
+	request := <-requests
+	assert.Len(t, request, 4)
+	assert.Equal(t, "test.tag", request[0])
+	assert.IsType(t, time.Time{}, request[1])
+	record := request[2].(map[string]interface{})
+	assert.Equal(t, "error", record["level"])
+	assert.Equal(t, log.MTest, record["code"])
+	assert.Equal(t, "Hello world!", record["message"])
+	assert.Equal(t, "foo", record["username"])
+	assert.Equal(t, map[string]interface{}{"a": int64(1)}, record["nested"])
+}
+
+func TestFluentForwardMessageModeWithoutBatchConfig(t *testing.T) {
+	listener, requests := startFluentForwardServer(t)
+	defer func() {
+		_ = listener.Close()
+	}()
+
+	config := newDestinationTestConfig(log.DestinationFluentForward)
+	config.FluentForward.Address = listener.Addr().String()
+	config.FluentForward.Tag = "test.tag"
+	config.FluentForward.Batch = log.BatchConfig{}
+	logger := log.MustNewLogger(config)
+	for i := 0; i < 10; i++ {
+		logger.Error(log.NewMessage(log.MTest, "Hello world!"))
+	}
+	assert.NoError(t, logger.Close())
+	assert.Eventually(t, func() bool {
+		return len(requests) == 10
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestFluentForwardForwardMode(t *testing.T) {
+	for _, mode := range []log.FluentForwardMode{log.FluentForwardModeForward, log.FluentForwardModePackedForward} {
+		t.Run(string(mode), func(t *testing.T) {
+			listener, requests := startFluentForwardServer(t)
+			defer func() {
+				_ = listener.Close()
+			}()
+
+			config := newDestinationTestConfig(log.DestinationFluentForward)
+			config.FluentForward.Address = listener.Addr().String()
+			config.FluentForward.Tag = "test.tag"
+			config.FluentForward.Mode = mode
+			logger := log.MustNewLogger(config)
+			logger.Error(log.NewMessage(log.MTest, "first"))
+			logger.Error(log.NewMessage(log.MTest, "second"))
+			assert.NoError(t, logger.Close())
+
+			request := <-requests
+			assert.Len(t, request, 3)
+			assert.Equal(t, "test.tag", request[0])
+			assert.Equal(t, map[string]interface{}{"size": int64(2)}, request[2])
+
+			var entries []interface{}
+			if mode == log.FluentForwardModePackedForward {
+				reader := bufio.NewReader(bytes.NewReader(request[1].([]byte)))
+				for {
+					entry, err := log.DecodeMsgpack(reader)
+					if err != nil {
+						break
+					}
+					entries = append(entries, entry)
+				}
+			} else {
+				entries = request[1].([]interface{})
+			}
+			assert.Len(t, entries, 2)
+			record := entries[1].([]interface{})[1].(map[string]interface{})
+			assert.Equal(t, "second", record["message"])
+		})
+	}
+}
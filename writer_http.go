@@ -0,0 +1,57 @@
+package log
+
+import (
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// sendHTTPRequest sends a request created by newRequest and retries it with an exponential backoff on transport errors
+// and when shouldRetry returns true for the response status code. A new request is created for every attempt since
+// request bodies can only be read once. It returns the status code and body of the last response.
+func sendHTTPRequest(
+	client *http.Client,
+	retry RetryConfig,
+	shouldRetry func(statusCode int) bool,
+	newRequest func() (*http.Request, error),
+) (int, []byte, error) {
+	backoff := retry.Backoff
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		statusCode, body, err := sendHTTPRequestOnce(client, newRequest)
+		if err == nil && !shouldRetry(statusCode) {
+			return statusCode, body, nil
+		}
+		lastErr = err
+		if attempt >= retry.Attempts {
+			if lastErr != nil {
+				return 0, nil, lastErr
+			}
+			return statusCode, body, nil
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > retry.MaxBackoff {
+			backoff = retry.MaxBackoff
+		}
+	}
+}
+
+func sendHTTPRequestOnce(client *http.Client, newRequest func() (*http.Request, error)) (int, []byte, error) {
+	req, err := newRequest()
+	if err != nil {
+		return 0, nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
@@ -0,0 +1,311 @@
+package log
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+)
+
+const otlpScopeName = "github.com/containerssh/log"
+
+// otlpSeverity maps the syslog levels to OpenTelemetry severity numbers as recommended by the OpenTelemetry log data
+// model for syslog sources.
+var otlpSeverity = map[Level]int32{
+	LevelDebug:     5,
+	LevelInfo:      9,
+	LevelNotice:    10,
+	LevelWarning:   13,
+	LevelError:     17,
+	LevelCritical:  18,
+	LevelAlert:     19,
+	LevelEmergency: 21,
+}
+
+func newOTLPWriter(config OTLPConfig, timeSource *timeSource) (Writer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	resource, err := newOTLPResource(config.Resource)
+	if err != nil {
+		return nil, err
+	}
+	w := &otlpWriter{
+		config:   config,
+		resource: resource,
+		client: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+	w.batchWriter = newBatchWriter(config.Batch, timeSource, w.send)
+	return w, nil
+}
+
+// otlpWriter inherits the batching behavior from batchWriter and exports the batches to an OTLP/HTTP endpoint.
+type otlpWriter struct {
+	*batchWriter
+
+	config   OTLPConfig
+	resource otlpResource
+	client   *http.Client
+}
+
+func newOTLPResource(config OTLPResourceConfig) (otlpResource, error) {
+	hostName := config.HostName
+	if hostName == "" {
+		var err error
+		if hostName, err = os.Hostname(); err != nil {
+			return otlpResource{}, fmt.Errorf("failed to determine host name for OTLP resource (%w)", err)
+		}
+	}
+	attributes := []otlpKeyValue{
+		otlpStringAttribute("service.name", config.ServiceName),
+		otlpStringAttribute("host.name", hostName),
+	}
+	var names []string
+	for name := range config.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		attributes = append(attributes, otlpStringAttribute(name, config.Attributes[name]))
+	}
+	return otlpResource{Attributes: attributes}, nil
+}
+
+func (o *otlpWriter) send(entries []batchEntry) error {
+	request := o.createRequest(entries)
+	var body []byte
+	var contentType string
+	var err error
+	switch o.config.Encoding {
+	case OTLPEncodingJSON:
+		contentType = "application/json"
+		body, err = json.Marshal(request)
+		if err != nil {
+			return Wrap(err, ELogWriteFailed, "failed to encode OTLP request")
+		}
+	default:
+		contentType = "application/x-protobuf"
+		body = request.marshalProto()
+	}
+	req, err := http.NewRequest(http.MethodPost, o.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return Wrap(err, ELogWriteFailed, "failed to create OTLP request")
+	}
+	req.Header.Set("Content-Type", contentType)
+	for name, value := range o.config.Headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return Wrap(err, ELogWriteFailed, "failed to send logs to OTLP endpoint %s", o.config.URL)
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return NewMessage(
+			ELogWriteFailed,
+			"OTLP endpoint %s rejected logs with status code %d",
+			o.config.URL,
+			resp.StatusCode,
+		)
+	}
+	return nil
+}
+
+func (o *otlpWriter) createRequest(entries []batchEntry) otlpExportRequest {
+	records := make([]otlpLogRecord, len(entries))
+	for i, entry := range entries {
+		records[i] = o.createRecord(entry)
+	}
+	return otlpExportRequest{
+		ResourceLogs: []otlpResourceLogs{
+			{
+				Resource: o.resource,
+				ScopeLogs: []otlpScopeLogs{
+					{
+						Scope:      otlpScope{Name: otlpScopeName},
+						LogRecords: records,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (o *otlpWriter) createRecord(entry batchEntry) otlpLogRecord {
+	body := entry.explanation
+	record := otlpLogRecord{
+		TimeUnixNano:         uint64(entry.time.UnixNano()),
+		ObservedTimeUnixNano: uint64(entry.time.UnixNano()),
+		SeverityNumber:       otlpSeverity[entry.level],
+		SeverityText:         string(entry.level.MustName()),
+		Body:                 otlpAnyValue{StringValue: &body},
+		Attributes:           []otlpKeyValue{otlpStringAttribute("code", entry.code)},
+	}
+	labels := make(Labels, len(entry.labels))
+	for name, value := range entry.labels {
+		labels[name] = value
+	}
+	record.TraceID, record.SpanID = extractTraceContext(labels, o.config.TraceIDLabel, o.config.SpanIDLabel)
+	var names []string
+	for name := range labels {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		record.Attributes = append(
+			record.Attributes,
+			otlpKeyValue{Key: name, Value: newOTLPAnyValue(labels[LabelName(name)])},
+		)
+	}
+	return record
+}
+
+func otlpStringAttribute(key string, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: &value}}
+}
+
+// newOTLPAnyValue converts a label value to the closest matching OTLP value type.
+func newOTLPAnyValue(value LabelValue) otlpAnyValue {
+	switch v := value.(type) {
+	case string:
+		return otlpAnyValue{StringValue: &v}
+	case bool:
+		return otlpAnyValue{BoolValue: &v}
+	case []byte:
+		return otlpAnyValue{BytesValue: v}
+	case time.Time:
+		s := v.Format(time.RFC3339Nano)
+		return otlpAnyValue{StringValue: &s}
+	case error:
+		s := v.Error()
+		return otlpAnyValue{StringValue: &s}
+	case fmt.Stringer:
+		s := v.String()
+		return otlpAnyValue{StringValue: &s}
+	}
+	return newOTLPAnyValueReflect(reflect.ValueOf(value))
+}
+
+func newOTLPAnyValueReflect(v reflect.Value) otlpAnyValue {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := v.Int()
+		return otlpAnyValue{IntValue: &i}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i := int64(v.Uint())
+		return otlpAnyValue{IntValue: &i}
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		return otlpAnyValue{DoubleValue: &f}
+	case reflect.Slice, reflect.Array:
+		values := &otlpArrayValue{Values: []otlpAnyValue{}}
+		for i := 0; i < v.Len(); i++ {
+			values.Values = append(values.Values, newOTLPAnyValue(v.Index(i).Interface()))
+		}
+		return otlpAnyValue{ArrayValue: values}
+	case reflect.Map:
+		values := &otlpKeyValueList{Values: []otlpKeyValue{}}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprintf("%v", keys[i].Interface()) < fmt.Sprintf("%v", keys[j].Interface())
+		})
+		for _, key := range keys {
+			values.Values = append(values.Values, otlpKeyValue{
+				Key:   fmt.Sprintf("%v", key.Interface()),
+				Value: newOTLPAnyValue(v.MapIndex(key).Interface()),
+			})
+		}
+		return otlpAnyValue{KvlistValue: values}
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			return newOTLPAnyValue(v.Elem().Interface())
+		}
+		return otlpAnyValue{}
+	case reflect.Invalid:
+		return otlpAnyValue{}
+	}
+	s := fmt.Sprintf("%v", v.Interface())
+	return otlpAnyValue{StringValue: &s}
+}
+
+// region OTLP data model
+
+// The following structures mirror the OTLP logs protobuf messages. The JSON tags follow the OTLP/JSON mapping, the
+// protobuf encoding is implemented in writer_otlp_proto.go.
+
+type otlpExportRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         uint64         `json:"timeUnixNano,string"`
+	ObservedTimeUnixNano uint64         `json:"observedTimeUnixNano,string"`
+	SeverityNumber       int32          `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes"`
+	TraceID              otlpID         `json:"traceId,omitempty"`
+	SpanID               otlpID         `json:"spanId,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string           `json:"stringValue,omitempty"`
+	BoolValue   *bool             `json:"boolValue,omitempty"`
+	IntValue    *int64            `json:"intValue,omitempty,string"`
+	DoubleValue *float64          `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue   `json:"arrayValue,omitempty"`
+	KvlistValue *otlpKeyValueList `json:"kvlistValue,omitempty"`
+	BytesValue  []byte            `json:"bytesValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+type otlpKeyValueList struct {
+	Values []otlpKeyValue `json:"values"`
+}
+
+// otlpID is a trace or span ID. OTLP/JSON encodes these as hex strings instead of base64.
+type otlpID []byte
+
+func (i otlpID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(i))
+}
+
+// endregion
@@ -0,0 +1,147 @@
+package log
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// This file contains a minimal protobuf encoder for the OTLP logs data model so the package does not need to depend
+// on the protobuf runtime. Field numbers follow opentelemetry/proto/collector/logs/v1/logs_service.proto and the
+// messages it references.
+
+const (
+	protoWireVarint  = 0
+	protoWireFixed64 = 1
+	protoWireBytes   = 2
+	protoWireFixed32 = 5
+)
+
+type protoBuffer []byte
+
+func (b *protoBuffer) tag(field int, wireType int) {
+	b.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (b *protoBuffer) varint(v uint64) {
+	for v >= 0x80 {
+		*b = append(*b, byte(v)|0x80)
+		v >>= 7
+	}
+	*b = append(*b, byte(v))
+}
+
+func (b *protoBuffer) varintField(field int, v uint64) {
+	b.tag(field, protoWireVarint)
+	b.varint(v)
+}
+
+func (b *protoBuffer) fixed64Field(field int, v uint64) {
+	b.tag(field, protoWireFixed64)
+	*b = append(*b, make([]byte, 8)...)
+	binary.LittleEndian.PutUint64((*b)[len(*b)-8:], v)
+}
+
+func (b *protoBuffer) bytesField(field int, v []byte) {
+	b.tag(field, protoWireBytes)
+	b.varint(uint64(len(v)))
+	*b = append(*b, v...)
+}
+
+func (b *protoBuffer) stringField(field int, v string) {
+	b.bytesField(field, []byte(v))
+}
+
+func (r otlpExportRequest) marshalProto() []byte {
+	var b protoBuffer
+	for _, resourceLogs := range r.ResourceLogs {
+		b.bytesField(1, resourceLogs.marshalProto())
+	}
+	return b
+}
+
+func (r otlpResourceLogs) marshalProto() []byte {
+	var b protoBuffer
+	b.bytesField(1, r.Resource.marshalProto())
+	for _, scopeLogs := range r.ScopeLogs {
+		b.bytesField(2, scopeLogs.marshalProto())
+	}
+	return b
+}
+
+func (r otlpResource) marshalProto() []byte {
+	var b protoBuffer
+	for _, attribute := range r.Attributes {
+		b.bytesField(1, attribute.marshalProto())
+	}
+	return b
+}
+
+func (s otlpScopeLogs) marshalProto() []byte {
+	var b protoBuffer
+	var scope protoBuffer
+	scope.stringField(1, s.Scope.Name)
+	b.bytesField(1, scope)
+	for _, record := range s.LogRecords {
+		b.bytesField(2, record.marshalProto())
+	}
+	return b
+}
+
+func (r otlpLogRecord) marshalProto() []byte {
+	var b protoBuffer
+	b.fixed64Field(1, r.TimeUnixNano)
+	b.varintField(2, uint64(r.SeverityNumber))
+	b.stringField(3, r.SeverityText)
+	b.bytesField(5, r.Body.marshalProto())
+	for _, attribute := range r.Attributes {
+		b.bytesField(6, attribute.marshalProto())
+	}
+	if len(r.TraceID) > 0 {
+		b.bytesField(9, r.TraceID)
+	}
+	if len(r.SpanID) > 0 {
+		b.bytesField(10, r.SpanID)
+	}
+	b.fixed64Field(11, r.ObservedTimeUnixNano)
+	return b
+}
+
+func (kv otlpKeyValue) marshalProto() []byte {
+	var b protoBuffer
+	b.stringField(1, kv.Key)
+	b.bytesField(2, kv.Value.marshalProto())
+	return b
+}
+
+func (v otlpAnyValue) marshalProto() []byte {
+	var b protoBuffer
+	switch {
+	case v.StringValue != nil:
+		b.stringField(1, *v.StringValue)
+	case v.BoolValue != nil:
+		value := uint64(0)
+		if *v.BoolValue {
+			value = 1
+		}
+		b.varintField(2, value)
+	case v.IntValue != nil:
+		b.varintField(3, uint64(*v.IntValue))
+	case v.DoubleValue != nil:
+		b.fixed64Field(4, math.Float64bits(*v.DoubleValue))
+	case v.ArrayValue != nil:
+		var values protoBuffer
+		for _, item := range v.ArrayValue.Values {
+			values.bytesField(1, item.marshalProto())
+		}
+		b.bytesField(5, values)
+	case v.KvlistValue != nil:
+		var values protoBuffer
+		for _, item := range v.KvlistValue.Values {
+			values.bytesField(1, item.marshalProto())
+		}
+		b.bytesField(6, values)
+	case v.BytesValue != nil:
+		b.bytesField(7, v.BytesValue)
+	}
+	return b
+}
@@ -0,0 +1,221 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func TestOTLPJSON(t *testing.T) {
+	server, requests, bodies := startHTTPTestServer(t)
+	defer server.Close()
+
+	config := newDestinationTestConfig(log.DestinationOTLP)
+	config.Level = log.LevelDebug
+	config.OTLP.URL = server.URL
+	config.OTLP.Encoding = log.OTLPEncodingJSON
+	config.OTLP.Resource.HostName = "testhost"
+	config.OTLP.Headers = map[string]string{"Authorization": "Bearer test"}
+	logger := log.MustNewLogger(config)
+	logger.Error(
+		log.NewMessage(log.MTest, "Hello world!").
+			Label("username", "foo").
+			Label("count", 5).
+			Label("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"),
+	)
+	assert.NoError(t, logger.Close())
+
+	request := <-requests
+	assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+	assert.Equal(t, "Bearer test", request.Header.Get("Authorization"))
+
+	data := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(<-bodies, &data))
+	resourceLogs := data["resourceLogs"].([]interface{})[0].(map[string]interface{})
+	resourceAttributes := resourceLogs["resource"].(map[string]interface{})["attributes"].([]interface{})
+	assert.Equal(t, "service.name", resourceAttributes[0].(map[string]interface{})["key"])
+	assert.Equal(t, "host.name", resourceAttributes[1].(map[string]interface{})["key"])
+
+	scopeLogs := resourceLogs["scopeLogs"].([]interface{})[0].(map[string]interface{})
+	record := scopeLogs["logRecords"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, float64(17), record["severityNumber"])
+	assert.Equal(t, "error", record["severityText"])
+	assert.Equal(t, "Hello world!", record["body"].(map[string]interface{})["stringValue"])
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", record["traceId"])
+	assert.Equal(t, "b7ad6b7169203331", record["spanId"])
+
+	attributes := map[string]interface{}{}
+	for _, attribute := range record["attributes"].([]interface{}) {
+		kv := attribute.(map[string]interface{})
+		attributes[kv["key"].(string)] = kv["value"]
+	}
+	assert.Equal(t, map[string]interface{}{"stringValue": log.MTest}, attributes["code"])
+	assert.Equal(t, map[string]interface{}{"stringValue": "foo"}, attributes["username"])
+	assert.Equal(t, map[string]interface{}{"intValue": "5"}, attributes["count"])
+	assert.NotContains(t, attributes, "traceparent")
+}
+
+func TestOTLPProtobuf(t *testing.T) {
+	server, requests, bodies := startHTTPTestServer(t)
+	defer server.Close()
+
+	now := time.Date(2021, 3, 4, 5, 6, 7, 8, time.UTC)
+	config := newDestinationTestConfig(log.DestinationOTLP)
+	config.OTLP.URL = server.URL
+	config.OTLP.Resource.HostName = "testhost"
+	config.Clock = func() time.Time {
+		return now
+	}
+	logger := log.MustNewLogger(config)
+	logger.Warning(
+		log.NewMessage(log.MTest, "Hello world!").
+			Label("username", "foo").
+			Label("count", 5).
+			Label("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"),
+	)
+	assert.NoError(t, logger.Close())
+
+	request := <-requests
+	assert.Equal(t, "application/x-protobuf", request.Header.Get("Content-Type"))
+
+	exportRequest := decodeProto(t, <-bodies)
+	resourceLogs := decodeProto(t, protoBytes(t, exportRequest[1]))
+	resource := decodeProto(t, protoBytes(t, resourceLogs[1]))
+	assert.Equal(t, protoFields{1: {[]byte("testhost")}}, decodeProtoAttributes(t, resource[1])["host.name"])
+
+	scopeLogs := decodeProto(t, protoBytes(t, resourceLogs[2]))
+	scope := decodeProto(t, protoBytes(t, scopeLogs[1]))
+	assert.Equal(t, protoFields{1: {[]byte("github.com/containerssh/log")}}, scope)
+
+	record := decodeProto(t, protoBytes(t, scopeLogs[2]))
+	traceID, _ := hex.DecodeString("0af7651916cd43dd8448eb211c80319c")
+	spanID, _ := hex.DecodeString("b7ad6b7169203331")
+	assert.Equal(t, protoFields{1: {[]byte("Hello world!")}}, decodeProto(t, protoBytes(t, record[5])))
+	attributes := record[6]
+	delete(record, 5)
+	delete(record, 6)
+	assert.Equal(t, protoFields{
+		1:  {uint64(now.UnixNano())},
+		2:  {uint64(13)},
+		3:  {[]byte("warning")},
+		9:  {traceID},
+		10: {spanID},
+		11: {uint64(now.UnixNano())},
+	}, record)
+	assert.Equal(t, map[string]protoFields{
+		"code":     {1: {[]byte(log.MTest)}},
+		"count":    {3: {uint64(5)}},
+		"username": {1: {[]byte("foo")}},
+	}, decodeProtoAttributes(t, attributes))
+}
+
+// protoFields contains the fields of a protobuf message keyed by field number. Varint and fixed64 values are stored as
+// uint64, length-delimited values as []byte.
+type protoFields map[int][]interface{}
+
+// decodeProto decodes a protobuf message without knowing its schema.
+func decodeProto(t *testing.T, data []byte) protoFields {
+	fields := protoFields{}
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if !assert.Greater(t, n, 0) {
+			return fields
+		}
+		data = data[n:]
+		field := int(tag >> 3)
+		switch tag & 7 {
+		case 0:
+			value, n := binary.Uvarint(data)
+			if !assert.Greater(t, n, 0) {
+				return fields
+			}
+			fields[field] = append(fields[field], value)
+			data = data[n:]
+		case 1:
+			if !assert.GreaterOrEqual(t, len(data), 8) {
+				return fields
+			}
+			fields[field] = append(fields[field], binary.LittleEndian.Uint64(data))
+			data = data[8:]
+		case 2:
+			length, n := binary.Uvarint(data)
+			if !assert.Greater(t, n, 0) || !assert.LessOrEqual(t, length, uint64(len(data)-n)) {
+				return fields
+			}
+			fields[field] = append(fields[field], data[n:n+int(length)])
+			data = data[n+int(length):]
+		default:
+			t.Fatalf("unexpected wire type in tag %d", tag)
+		}
+	}
+	return fields
+}
+
+// protoBytes returns the value of a field that must occur exactly once and be length-delimited.
+func protoBytes(t *testing.T, values []interface{}) []byte {
+	if !assert.Len(t, values, 1) {
+		return nil
+	}
+	data, ok := values[0].([]byte)
+	assert.True(t, ok)
+	return data
+}
+
+// decodeProtoAttributes decodes repeated KeyValue messages into the fields of their AnyValue keyed by the key.
+func decodeProtoAttributes(t *testing.T, values []interface{}) map[string]protoFields {
+	result := map[string]protoFields{}
+	for _, value := range values {
+		data, ok := value.([]byte)
+		assert.True(t, ok)
+		kv := decodeProto(t, data)
+		result[string(protoBytes(t, kv[1]))] = decodeProto(t, protoBytes(t, kv[2]))
+	}
+	return result
+}
+
+func TestOTLPBatchInterval(t *testing.T) {
+	server, _, bodies := startHTTPTestServer(t)
+	defer server.Close()
+
+	config := newDestinationTestConfig(log.DestinationOTLP)
+	config.OTLP.URL = server.URL
+	config.OTLP.Encoding = log.OTLPEncodingJSON
+	config.OTLP.Batch.Interval = 10 * time.Millisecond
+	logger := log.MustNewLogger(config)
+	defer func() {
+		assert.NoError(t, logger.Close())
+	}()
+
+	logger.Error(log.NewMessage(log.MTest, "first"))
+	logger.Error(log.NewMessage(log.MTest, "second"))
+	select {
+	case body := <-bodies:
+		assert.True(t, bytes.Contains(body, []byte("first")))
+		assert.True(t, bytes.Contains(body, []byte("second")))
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "batch was not sent within the interval")
+	}
+}
+
+func TestOTLPServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	config := newDestinationTestConfig(log.DestinationOTLP)
+	config.OTLP.URL = server.URL
+	config.OTLP.Encoding = log.OTLPEncodingJSON
+	logger := log.MustNewLogger(config)
+	logger.Error(log.NewMessage(log.MTest, "Hello world!"))
+	assert.Error(t, logger.Close())
+}
@@ -0,0 +1,205 @@
+package log
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+func newSplunkHECWriter(config SplunkHECConfig, timeSource *timeSource) (Writer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if config.Host == "" {
+		hostName, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine host name for Splunk HEC (%w)", err)
+		}
+		config.Host = hostName
+	}
+	if config.Ack && config.Channel == "" {
+		channel, err := newRandomUUID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate Splunk HEC channel ID (%w)", err)
+		}
+		config.Channel = channel
+	}
+	w := &splunkHECWriter{
+		config:  config,
+		baseURL: strings.TrimSuffix(config.URL, "/"),
+		client: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+	w.batchWriter = newBatchWriter(config.Batch, timeSource, w.send)
+	return w, nil
+}
+
+// splunkHECWriter inherits the batching behavior from batchWriter and sends the batches to the Splunk HTTP Event
+// Collector.
+type splunkHECWriter struct {
+	*batchWriter
+
+	config  SplunkHECConfig
+	baseURL string
+	client  *http.Client
+}
+
+type splunkHECEvent struct {
+	Time       json.Number        `json:"time"`
+	Host       string             `json:"host,omitempty"`
+	Source     string             `json:"source,omitempty"`
+	SourceType string             `json:"sourcetype,omitempty"`
+	Index      string             `json:"index,omitempty"`
+	Event      splunkHECEventData `json:"event"`
+}
+
+type splunkHECEventData struct {
+	Level   string                 `json:"level"`
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details"`
+}
+
+type splunkHECResponse struct {
+	Text  string `json:"text"`
+	Code  int    `json:"code"`
+	AckID *int64 `json:"ackId"`
+}
+
+type splunkHECAckResponse struct {
+	Acks map[string]bool `json:"acks"`
+}
+
+func (s *splunkHECWriter) send(entries []batchEntry) error {
+	body, err := s.createBody(entries)
+	if err != nil {
+		return Wrap(err, ELogWriteFailed, "failed to encode Splunk HEC events")
+	}
+	responseBody, err := s.post("/services/collector/event", body)
+	if err != nil {
+		return err
+	}
+	if !s.config.Ack {
+		return nil
+	}
+	response := splunkHECResponse{}
+	if err := json.Unmarshal(responseBody, &response); err != nil || response.AckID == nil {
+		return NewMessage(ELogWriteFailed, "Splunk HEC did not return an acknowledgement ID")
+	}
+	return s.waitForAck(*response.AckID)
+}
+
+func (s *splunkHECWriter) createBody(entries []batchEntry) ([]byte, error) {
+	var body bytes.Buffer
+	for _, entry := range entries {
+		details := map[string]interface{}{}
+		for label, value := range entry.labels {
+			details[string(label)] = value
+		}
+		event, err := json.Marshal(
+			splunkHECEvent{
+				Time:       json.Number(fmt.Sprintf("%.3f", float64(entry.time.UnixNano())/float64(time.Second))),
+				Host:       s.config.Host,
+				Source:     s.config.Source,
+				SourceType: s.config.SourceType,
+				Index:      s.config.Index,
+				Event: splunkHECEventData{
+					Level:   string(entry.level.MustName()),
+					Code:    entry.code,
+					Message: entry.explanation,
+					Details: details,
+				},
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		body.Write(event)
+		body.WriteByte('\n')
+	}
+	return body.Bytes(), nil
+}
+
+func (s *splunkHECWriter) waitForAck(ackID int64) error {
+	body, err := json.Marshal(map[string][]int64{"acks": {ackID}})
+	if err != nil {
+		return Wrap(err, ELogWriteFailed, "failed to encode Splunk HEC acknowledgement request")
+	}
+	deadline := time.Now().Add(s.config.AckTimeout)
+	for {
+		responseBody, err := s.post("/services/collector/ack", body)
+		if err != nil {
+			return err
+		}
+		response := splunkHECAckResponse{}
+		if err := json.Unmarshal(responseBody, &response); err != nil {
+			return Wrap(err, ELogWriteFailed, "failed to decode Splunk HEC acknowledgement response")
+		}
+		if response.Acks[fmt.Sprintf("%d", ackID)] {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return NewMessage(
+				ELogWriteFailed,
+				"Splunk HEC did not acknowledge events with ack ID %d within %s",
+				ackID,
+				s.config.AckTimeout,
+			)
+		}
+		time.Sleep(s.config.AckInterval)
+	}
+}
+
+// post sends a request to the specified HEC endpoint, retrying if the server is busy.
+func (s *splunkHECWriter) post(path string, body []byte) ([]byte, error) {
+	endpoint := s.baseURL + path
+	statusCode, responseBody, err := sendHTTPRequest(
+		s.client,
+		s.config.Retry,
+		func(statusCode int) bool {
+			return statusCode == http.StatusServiceUnavailable
+		},
+		func() (*http.Request, error) {
+			req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Authorization", "Splunk "+s.config.Token)
+			req.Header.Set("Content-Type", "application/json")
+			if s.config.Channel != "" {
+				req.Header.Set("X-Splunk-Request-Channel", s.config.Channel)
+			}
+			return req, nil
+		},
+	)
+	if err != nil {
+		return nil, Wrap(err, ELogWriteFailed, "failed to send request to Splunk HEC %s", endpoint)
+	}
+	if statusCode < 200 || statusCode > 299 {
+		return nil, NewMessage(
+			ELogWriteFailed,
+			"Splunk HEC %s rejected request with status code %d (%s)",
+			endpoint,
+			statusCode,
+			strings.TrimSpace(string(responseBody)),
+		)
+	}
+	return responseBody, nil
+}
+
+// newRandomUUID generates a random (version 4) UUID.
+func newRandomUUID() (string, error) {
+	data := make([]byte, 16)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	data[6] = (data[6] & 0x0f) | 0x40
+	data[8] = (data[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", data[0:4], data[4:6], data[6:8], data[8:10], data[10:]), nil
+}
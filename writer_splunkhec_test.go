@@ -0,0 +1,131 @@
+package log_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+type splunkHECTestServer struct {
+	lock           *sync.Mutex
+	unavailable    int
+	events         []map[string]interface{}
+	authorizations []string
+	ackRequests    int
+}
+
+func (s *splunkHECTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.authorizations = append(s.authorizations, r.Header.Get("Authorization"))
+	if s.unavailable > 0 {
+		s.unavailable--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	switch r.URL.Path {
+	case "/services/collector/event":
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			event := map[string]interface{}{}
+			if err := json.Unmarshal(scanner.Bytes(), &event); err == nil {
+				s.events = append(s.events, event)
+			}
+		}
+		if r.Header.Get("X-Splunk-Request-Channel") != "" {
+			_, _ = w.Write([]byte(`{"text":"Success","code":0,"ackId":42}`))
+		} else {
+			_, _ = w.Write([]byte(`{"text":"Success","code":0}`))
+		}
+	case "/services/collector/ack":
+		// Acknowledge the events on the second status request only.
+		s.ackRequests++
+		if s.ackRequests > 1 {
+			_, _ = w.Write([]byte(`{"acks":{"42":true}}`))
+		} else {
+			_, _ = w.Write([]byte(`{"acks":{"42":false}}`))
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestSplunkHEC(t *testing.T) {
+	handler := &splunkHECTestServer{lock: &sync.Mutex{}, unavailable: 2}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := newDestinationTestConfig(log.DestinationSplunkHEC)
+	config.SplunkHEC.URL = server.URL
+	config.SplunkHEC.Token = "secret"
+	config.SplunkHEC.Index = "main"
+	config.SplunkHEC.Retry.Backoff = time.Millisecond
+	logger := log.MustNewLogger(config)
+	logger.Error(log.NewMessage(log.MTest, "first").Label("username", "foo"))
+	logger.Warning(log.NewMessage(log.MTest, "second"))
+	assert.NoError(t, logger.Close())
+
+	handler.lock.Lock()
+	defer handler.lock.Unlock()
+	assert.Len(t, handler.authorizations, 3)
+	assert.Equal(t, "Splunk secret", handler.authorizations[2])
+	assert.Len(t, handler.events, 2)
+	assert.Equal(t, "containerssh", handler.events[0]["sourcetype"])
+	assert.Equal(t, "main", handler.events[0]["index"])
+	event := handler.events[0]["event"].(map[string]interface{})
+	assert.Equal(t, "error", event["level"])
+	assert.Equal(t, log.MTest, event["code"])
+	assert.Equal(t, "first", event["message"])
+	assert.Equal(t, map[string]interface{}{"username": "foo"}, event["details"])
+}
+
+func TestSplunkHECAck(t *testing.T) {
+	handler := &splunkHECTestServer{lock: &sync.Mutex{}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := newDestinationTestConfig(log.DestinationSplunkHEC)
+	config.SplunkHEC.URL = server.URL
+	config.SplunkHEC.Token = "secret"
+	config.SplunkHEC.Retry.Backoff = time.Millisecond
+	config.SplunkHEC.Ack = true
+	config.SplunkHEC.AckInterval = time.Millisecond
+	logger := log.MustNewLogger(config)
+	logger.Error(log.NewMessage(log.MTest, "Hello world!"))
+	assert.NoError(t, logger.Close())
+
+	handler.lock.Lock()
+	defer handler.lock.Unlock()
+	assert.Len(t, handler.events, 1)
+	assert.Equal(t, 2, handler.ackRequests)
+}
+
+func TestSplunkHECUnavailable(t *testing.T) {
+	handler := &splunkHECTestServer{lock: &sync.Mutex{}, unavailable: 10}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config := newDestinationTestConfig(log.DestinationSplunkHEC)
+	config.SplunkHEC.URL = server.URL
+	config.SplunkHEC.Token = "secret"
+	config.SplunkHEC.Retry.Backoff = time.Millisecond
+	config.SplunkHEC.Retry.Attempts = 3
+	logger := log.MustNewLogger(config)
+	logger.Error(log.NewMessage(log.MTest, "Hello world!"))
+	assert.Error(t, logger.Close())
+
+	handler.lock.Lock()
+	defer handler.lock.Unlock()
+	assert.Len(t, handler.authorizations, 3)
+}
@@ -6,9 +6,13 @@ import (
 )
 
 // newStdoutWriter creates a log writer that writes to the stdout (io.Writer) in the specified format.
-func newStdoutWriter(stdout io.Writer, format Format) (Writer, error) {
+func newStdoutWriter(stdout io.Writer, config Config) (Writer, error) {
+	fileHandleWriter, err := newFileHandleWriter(stdout, config, &sync.Mutex{})
+	if err != nil {
+		return nil, err
+	}
 	return &stdoutWriter{
-		fileHandleWriter: newFileHandleWriter(stdout, format, &sync.Mutex{}),
+		fileHandleWriter: fileHandleWriter,
 	}, nil
 }
 
@@ -1,24 +1,48 @@
 package log
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
 	"net"
 	"os"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 )
 
-func newSyslogWriter(config SyslogConfig, format Format) (Writer, error) {
-	if err := config.Validate(); err != nil {
+func newSyslogWriter(config Config) (Writer, error) {
+	syslogConfig := config.Syslog
+	if err := syslogConfig.Validate(); err != nil {
 		return nil, err
 	}
+	if config.Format.isBinary() || config.Format == FormatConsole {
+		return nil, fmt.Errorf("the %s log format is not supported by the syslog destination", config.Format)
+	}
+	var tpl *template.Template
+	if config.Format == FormatTemplate {
+		var err error
+		if tpl, err = parseLineTemplate(config.Template); err != nil {
+			return nil, err
+		}
+	}
+	var ljson *ljsonFormatter
+	if config.Format == FormatLJSON {
+		var err error
+		if ljson, err = newLJSONFormatter(config.LJSON); err != nil {
+			return nil, err
+		}
+	}
 	return &syslogWriter{
 		lock:       &sync.Mutex{},
-		connection: config.connection,
-		config:     config,
-		format:     format,
+		connection: syslogConfig.connection,
+		config:     syslogConfig,
+		format:     config.Format,
+		device:     config.Device,
+		gcp:        config.GCP,
+		template:   tpl,
+		ljson:      ljson,
+		timeSource: newTimeSource(config),
 	}, nil
 }
 
@@ -27,6 +51,11 @@ type syslogWriter struct {
 	config     SyslogConfig
 	lock       *sync.Mutex
 	format     Format
+	device     DeviceConfig
+	gcp        GCPConfig
+	template   *template.Template
+	ljson      *ljsonFormatter
+	timeSource *timeSource
 }
 
 func (s *syslogWriter) Write(level Level, message Message) error {
@@ -37,10 +66,11 @@ func (s *syslogWriter) Write(level Level, message Message) error {
 		return err
 	}
 	pri := int64(facilityNumber)*8 + int64(level)
-	t := time.Now()
+	// The syslog protocol mandates the timestamp format, so only the clock and time zone settings are applied.
+	t := s.timeSource.now()
 	timestamp := fmt.Sprintf(
 		"%s %2d %02d:%02d:%02d",
-		t.Format("Feb"),
+		t.Format("Jan"),
 		t.Day(),
 		t.Hour(),
 		t.Minute(),
@@ -49,7 +79,7 @@ func (s *syslogWriter) Write(level Level, message Message) error {
 	if s.config.Pid {
 		tag += fmt.Sprintf("[%d]", os.Getpid())
 	}
-	msg, err := s.createMessage(message)
+	msg, err := s.createMessage(t, level, message)
 	if err != nil {
 		return err
 	}
@@ -60,43 +90,39 @@ func (s *syslogWriter) Write(level Level, message Message) error {
 	return nil
 }
 
-func (s *syslogWriter) createMessage(message Message) (line []byte, err error) {
+// createMessage formats the message part of the syslog line. The formats producing a trailing newline have it removed
+// since the syslog line is terminated separately.
+func (s *syslogWriter) createMessage(t time.Time, level Level, message Message) (line []byte, err error) {
+	levelString, err := level.Name()
+	if err != nil {
+		return nil, err
+	}
 	switch s.format {
 	case FormatLJSON:
-		details := map[string]interface{}{}
-		for label, value := range message.Labels() {
-			details[string(label)] = value
-		}
-		line, err = json.Marshal(
-			syslogJsonLine{
-				Code:    message.Code(),
-				Message: message.Explanation(),
-				Details: details,
-			},
-		)
-		if err != nil {
-			return nil, err
-		}
+		line, err = s.ljson.createLine(s.timeSource.value(t), levelString, message)
 	case FormatText:
 		msg := message.Explanation()
-		var labels []string
-		for labelName, labelValue := range message.Labels() {
-			labels = append(labels, fmt.Sprintf("%s=%s", labelName, labelValue))
-		}
-		if len(labels) > 0 {
+		if labels := formatTextLabels(message.Labels()); len(labels) > 0 {
 			msg += fmt.Sprintf(" (%s)", strings.Join(labels, " "))
 		}
 		line = []byte(msg)
+	case FormatECS:
+		line, err = createLineECS(t, levelString, message)
+	case FormatGCP:
+		line, err = createLineGCP(t, levelString, message, s.gcp)
+	case FormatCEF:
+		line = createLineCEF(t, levelString, message, s.device)
+	case FormatLEEF:
+		line = createLineLEEF(t, levelString, message, s.device)
+	case FormatTemplate:
+		line, err = createLineTemplate(s.template, t, levelString, message)
 	default:
 		return nil, fmt.Errorf("log format not supported: %s", s.format)
 	}
-	return line, nil
-}
-
-type syslogJsonLine struct {
-	Code    string                 `json:"code"`
-	Message string                 `json:"message"`
-	Details map[string]interface{} `json:"details"`
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(line, "\n"), nil
 }
 
 func (s *syslogWriter) Rotate() error {
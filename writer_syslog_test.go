@@ -0,0 +1,69 @@
+package log_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containerssh/structutils"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func TestSyslogFormats(t *testing.T) {
+	for format, expected := range map[log.Format]string{
+		log.FormatLJSON:    `"msg":"Hello world!"`,
+		log.FormatText:     "Hello world! (username=foo)",
+		log.FormatECS:      `"message":"Hello world!"`,
+		log.FormatGCP:      `"message":"Hello world!"`,
+		log.FormatCEF:      "CEF:0|",
+		log.FormatLEEF:     "LEEF:1.0|",
+		log.FormatTemplate: "TEST Hello world! foo",
+	} {
+		t.Run(string(format), func(t *testing.T) {
+			connection, err := net.ListenPacket("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = connection.Close()
+			}()
+
+			config := log.Config{}
+			structutils.Defaults(&config)
+			config.Format = format
+			config.Destination = log.DestinationSyslog
+			config.Syslog.Destination = connection.LocalAddr().String()
+			config.LJSON.Fields.Message = "msg"
+			config.Template = "{{ .Code }} {{ .Explanation }} {{ .Labels.username }}\n"
+			logger := log.MustNewLogger(config)
+			logger.Error(log.NewMessage(log.MTest, "Hello world!").Label("username", "foo"))
+			assert.NoError(t, logger.Close())
+
+			assert.NoError(t, connection.SetReadDeadline(time.Now().Add(10*time.Second)))
+			buf := make([]byte, 4096)
+			n, _, err := connection.ReadFrom(buf)
+			assert.NoError(t, err)
+			line := string(buf[:n])
+			assert.Contains(t, line, expected)
+			assert.True(t, strings.HasSuffix(line, "\n"))
+			assert.Equal(t, 1, strings.Count(line, "\n"))
+		})
+	}
+}
+
+func TestSyslogUnsupportedFormats(t *testing.T) {
+	for _, format := range []log.Format{log.FormatConsole, log.FormatCBOR, log.FormatMsgpack} {
+		t.Run(string(format), func(t *testing.T) {
+			config := log.Config{}
+			structutils.Defaults(&config)
+			config.Format = format
+			config.Destination = log.DestinationSyslog
+			assert.Error(t, config.Validate())
+			_, err := log.NewLogger(config)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+)
+
+func newWebhookWriter(config WebhookConfig, timeSource *timeSource) (Writer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	tpl, err := parseWebhookTemplate(config.Template)
+	if err != nil {
+		return nil, err
+	}
+	w := &webhookWriter{
+		config:   config,
+		template: tpl,
+		client: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+	w.batchWriter = newBatchWriter(config.Batch, timeSource, w.send)
+	return w, nil
+}
+
+func parseWebhookTemplate(text string) (*template.Template, error) {
+	tpl, err := template.New("webhook").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook template (%w)", err)
+	}
+	return tpl, nil
+}
+
+// webhookWriter inherits the batching behavior from batchWriter and renders each batch through the configured
+// template before sending it to the webhook.
+type webhookWriter struct {
+	*batchWriter
+
+	config   WebhookConfig
+	template *template.Template
+	client   *http.Client
+}
+
+// webhookTemplateData is passed to the webhook template. The embedded message is the first message in the batch.
+type webhookTemplateData struct {
+	templateMessage
+
+	Messages []templateMessage
+}
+
+func (w *webhookWriter) Write(level Level, message Message) error {
+	if level > w.config.Level {
+		return nil
+	}
+	return w.batchWriter.Write(level, message)
+}
+
+func (w *webhookWriter) send(entries []batchEntry) error {
+	data := webhookTemplateData{}
+	for _, entry := range entries {
+		data.Messages = append(data.Messages, newTemplateMessage(
+			entry.time,
+			entry.level.MustName(),
+			entry.code,
+			entry.userMessage,
+			entry.explanation,
+			entry.labels,
+		))
+	}
+	data.templateMessage = data.Messages[0]
+
+	body := &bytes.Buffer{}
+	if err := w.template.Execute(body, data); err != nil {
+		return Wrap(err, ELogWriteFailed, "failed to render webhook template")
+	}
+	statusCode, responseBody, err := sendHTTPRequest(
+		w.client,
+		w.config.Retry,
+		func(statusCode int) bool {
+			return statusCode == http.StatusTooManyRequests || statusCode >= 500
+		},
+		func() (*http.Request, error) {
+			req, err := http.NewRequest(w.config.Method, w.config.URL, bytes.NewReader(body.Bytes()))
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Content-Type", w.config.ContentType)
+			for name, value := range w.config.Headers {
+				req.Header.Set(name, value)
+			}
+			return req, nil
+		},
+	)
+	if err != nil {
+		return Wrap(err, ELogWriteFailed, "failed to send log message to webhook %s", w.config.URL)
+	}
+	if statusCode < 200 || statusCode > 299 {
+		return NewMessage(
+			ELogWriteFailed,
+			"webhook %s rejected request with status code %d (%s)",
+			w.config.URL,
+			statusCode,
+			strings.TrimSpace(string(responseBody)),
+		)
+	}
+	return nil
+}
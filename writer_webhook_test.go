@@ -0,0 +1,116 @@
+package log_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/containerssh/log"
+)
+
+func TestWebhookDefaultTemplate(t *testing.T) {
+	server, requests, bodies := startHTTPTestServer(t, http.StatusBadGateway)
+	defer server.Close()
+
+	config := newDestinationTestConfig(log.DestinationWebhook)
+	config.Webhook.URL = server.URL
+	config.Webhook.Retry.Backoff = time.Millisecond
+	config.Webhook.Headers = map[string]string{"X-Test": "yes"}
+	logger := log.MustNewLogger(config)
+	logger.Error(log.NewMessage(log.MTest, "below the minimum level"))
+	logger.Critical(log.NewMessage(log.MTest, "Hello world!").Label("username", "foo"))
+	assert.NoError(t, logger.Close())
+
+	request := <-requests
+	assert.Equal(t, http.MethodPost, request.Method)
+	assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+	assert.Equal(t, "yes", request.Header.Get("X-Test"))
+	data := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(<-bodies, &data))
+	assert.Equal(t, map[string]interface{}{
+		"level":   "crit",
+		"code":    log.MTest,
+		"message": "Hello world!",
+		"labels":  map[string]interface{}{"username": "foo"},
+	}, data)
+	assert.Len(t, requests, 0)
+}
+
+func TestWebhookBatchTemplate(t *testing.T) {
+	server, requests, bodies := startHTTPTestServer(t)
+	defer server.Close()
+
+	config := newDestinationTestConfig(log.DestinationWebhook)
+	config.Webhook.URL = server.URL
+	config.Webhook.Retry.Backoff = time.Millisecond
+	config.Webhook.Method = http.MethodPut
+	config.Webhook.ContentType = "text/plain"
+	config.Webhook.Batch.Size = 2
+	config.Webhook.Template = "{{ range .Messages }}{{ upper .Code }} {{ .UserMessage }} {{ .Labels.username }};{{ end }}"
+	logger := log.MustNewLogger(config)
+	logger.Alert(log.UserMessage("first", "First message", "first").Label("username", "foo"))
+	logger.Emergency(log.UserMessage("second", "Second message", "second").Label("username", "bar"))
+	assert.NoError(t, logger.Close())
+
+	request := <-requests
+	assert.Equal(t, http.MethodPut, request.Method)
+	assert.Equal(t, "FIRST First message foo;SECOND Second message bar;", string(<-bodies))
+}
+
+func TestWebhookInvalidTemplate(t *testing.T) {
+	config := newDestinationTestConfig(log.DestinationWebhook)
+	config.Webhook.URL = "http://localhost/"
+	config.Webhook.Template = "{{ .Code "
+	assert.Error(t, config.Validate())
+	_, err := log.NewLogger(config)
+	assert.Error(t, err)
+}
+
+func TestWebhookSlowServerDoesNotBlockWrite(t *testing.T) {
+	release := make(chan struct{})
+	received := make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		received <- struct{}{}
+	}))
+	defer server.Close()
+
+	config := newDestinationTestConfig(log.DestinationWebhook)
+	config.Webhook.URL = server.URL
+	config.Webhook.Retry.Backoff = time.Millisecond
+	config.Webhook.Batch.Size = 1
+	logger := log.MustNewLogger(config)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		logger.Critical(log.NewMessage(log.MTest, "Hello world!"))
+	}
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	close(release)
+	assert.NoError(t, logger.Close())
+	assert.NoError(t, logger.Close())
+	assert.Len(t, received, 3)
+}
+
+func TestWebhookFullQueueDropsMessages(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+
+	config := newDestinationTestConfig(log.DestinationWebhook)
+	config.Webhook.URL = server.URL
+	config.Webhook.Retry.Backoff = time.Millisecond
+	config.Webhook.Batch.Size = 1
+	config.Webhook.Batch.QueueSize = 1
+	logger := log.MustNewLogger(config)
+	for i := 0; i < 5; i++ {
+		logger.Critical(log.NewMessage(log.MTest, "Hello world!"))
+	}
+	close(release)
+	assert.Error(t, logger.Close())
+}